@@ -0,0 +1,96 @@
+// Command easyvk runs tasks built on top of the easyvk package.
+//
+// Usage:
+//
+//	easyvk market-sync -group 123 -feed catalog.csv [-state catalog.state.json] [-dry-run] [-delete-missing]
+//
+// The access token is taken from the -token flag or VK_TOKEN variable.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/vorkytaka/easyvk-go/easyvk"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	switch os.Args[1] {
+	case "market-sync":
+		marketSync(os.Args[2:])
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: easyvk <command> [flags]")
+	fmt.Fprintln(os.Stderr, "commands:")
+	fmt.Fprintln(os.Stderr, "  market-sync  mirror a CSV or JSON product feed to the community shop")
+	os.Exit(2)
+}
+
+func marketSync(args []string) {
+	flags := flag.NewFlagSet("market-sync", flag.ExitOnError)
+	token := flags.String("token", "", "user access token with market and photos scope, VK_TOKEN by default")
+	groupID := flags.Int("group", 0, "ID of the community")
+	feed := flags.String("feed", "", "path to the CSV or JSON feed")
+	state := flags.String("state", "", "path to the state file, <feed>.state.json by default")
+	dryRun := flags.Bool("dry-run", false, "print the plan without changing the shop")
+	deleteMissing := flags.Bool("delete-missing", false, "delete products with SKU which is not in the feed")
+	flags.Parse(args)
+	// not a default value of the flag, so usage doesn't print the token
+	if *token == "" {
+		*token = os.Getenv("VK_TOKEN")
+	}
+
+	if *token == "" || *groupID == 0 || *feed == "" {
+		flags.Usage()
+		os.Exit(2)
+	}
+	if *state == "" {
+		*state = *feed + ".state.json"
+	}
+
+	items, err := easyvk.ReadCatalogFeed(*feed)
+	if err != nil {
+		fail(err)
+	}
+
+	sync := easyvk.NewCatalogSync(easyvk.WithToken(*token), *groupID, *state)
+	sync.DeleteMissing = *deleteMissing
+	plan, err := sync.Plan(items)
+	if err != nil {
+		fail(err)
+	}
+	for _, action := range plan.Actions {
+		fmt.Println(action)
+	}
+	fmt.Printf("%d to change, %d unchanged\n", len(plan.Actions), plan.Unchanged)
+	if *dryRun || len(plan.Actions) == 0 {
+		return
+	}
+
+	report, err := sync.Apply(plan)
+	if err != nil {
+		fail(err)
+	}
+	for _, failure := range report.Failed {
+		fmt.Fprintf(os.Stderr, "failed to %s: %v\n", failure.Action, failure.Err)
+	}
+	fmt.Printf("created %d, updated %d, deleted %d, unchanged %d, uploaded %d photos, failed %d\n",
+		report.Created, report.Updated, report.Deleted, report.Unchanged,
+		report.PhotosUploaded, len(report.Failed))
+	if len(report.Failed) > 0 {
+		os.Exit(1)
+	}
+}
+
+func fail(err error) {
+	fmt.Fprintln(os.Stderr, "easyvk:", err)
+	os.Exit(1)
+}
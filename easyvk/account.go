@@ -6,37 +6,6 @@ import (
 	"strconv"
 )
 
-const (
-	notifyBit = 1 << iota
-	friendsBit
-	photosBit
-	audioBit
-	videoBit
-	_
-	_
-	pagesBit
-	_
-	_
-	statusBit
-	notesBit
-	messagesBit
-	wallBit
-	_
-	adsBit
-	offlineBit
-	docsBit
-	groupsBit
-	notificationsBit
-	statsBit
-	_
-	emailBit
-	_
-	_
-	_
-	_
-	marketBit
-)
-
 // An Account describes a set of methods
 // to work with account.
 // https://vk.com/dev/account
@@ -78,7 +47,7 @@ type AccountGetProfileInfoResponse struct {
 	FirstName       string `json:"first_name"`
 	LastName        string `json:"last_name"`
 	ScreenName      string `json:"screen_name"`
-	Sex             int    `json:"sex"`
+	Sex             Sex    `json:"sex"`
 	Relation        int    `json:"relation"`
 	Birthday        string `json:"bdate"`
 	BirthVisibility int    `json:"bdate_visibility"`
@@ -142,65 +111,22 @@ func (a *Account) GetCounters(filter string) (AccountGetCountersResponse, error)
 	return counters, nil
 }
 
-// A AccountGetAppPermissionsResponse describes a set of app's permissions.
-// https://vk.com/dev/account.getAppPermissions
-type AccountGetAppPermissionsResponse struct {
-	Notify        bool
-	Friends       bool
-	Photos        bool
-	Audio         bool
-	Video         bool
-	Pages         bool
-	Status        bool
-	Notes         bool
-	Messages      bool
-	Wall          bool
-	Ads           bool
-	Offline       bool
-	Docs          bool
-	Groups        bool
-	Notifications bool
-	Stats         bool
-	Email         bool
-	Market        bool
-}
-
 // GetAppPermissions returns settings of the user in this application.
+// For the community token the mask is the community scope,
+// convert it with GroupScope(scope).
 // https://vk.com/dev/account.getAppPermissions
-func (a *Account) GetAppPermissions(userID uint) (AccountGetAppPermissionsResponse, error) {
+func (a *Account) GetAppPermissions(userID uint) (Scope, error) {
 	params := map[string]string{"user_id": fmt.Sprint(userID)}
 	resp, err := a.vk.Request("account.getAppPermissions", params)
 	if err != nil {
-		return AccountGetAppPermissionsResponse{}, err
+		return 0, err
 	}
 
-	permBits, err := strconv.ParseUint(string(resp), 10, 64)
+	mask, err := strconv.ParseUint(string(resp), 10, 64)
 	if err != nil {
-		return AccountGetAppPermissionsResponse{}, err
+		return 0, err
 	}
-
-	perm := AccountGetAppPermissionsResponse{}
-
-	perm.Notify = permBits&notifyBit != 0
-	perm.Friends = permBits&friendsBit != 0
-	perm.Photos = permBits&photosBit != 0
-	perm.Audio = permBits&audioBit != 0
-	perm.Video = permBits&videoBit != 0
-	perm.Pages = permBits&pagesBit != 0
-	perm.Status = permBits&statusBit != 0
-	perm.Notes = permBits&notesBit != 0
-	perm.Messages = permBits&messagesBit != 0
-	perm.Wall = permBits&wallBit != 0
-	perm.Ads = permBits&adsBit != 0
-	perm.Offline = permBits&offlineBit != 0
-	perm.Docs = permBits&docsBit != 0
-	perm.Groups = permBits&groupsBit != 0
-	perm.Notifications = permBits&notificationsBit != 0
-	perm.Stats = permBits&statsBit != 0
-	perm.Email = permBits&emailBit != 0
-	perm.Market = permBits&marketBit != 0
-
-	return perm, nil
+	return Scope(mask), nil
 }
 
 // A AccountGetBannedResponse describes a user's blacklist.
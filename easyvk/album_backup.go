@@ -0,0 +1,245 @@
+package easyvk
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// photosPerGet is the max count of photos.get.
+const photosPerGet = 1000
+
+// AlbumBackupParams provides fields for BackupAlbums params.
+type AlbumBackupParams struct {
+	// Owner of the albums, negative for communities.
+	OwnerID int
+	// Root of the backup, every album goes to Dir/<album id>.
+	Dir string
+	// Albums to back up, all albums of the owner if empty.
+	AlbumIDs []int
+	// Number of parallel downloads, 4 by default.
+	Workers int
+	// Limit of API requests per second, 3 by default.
+	RequestsPerSecond int
+	// Client for downloads, http.DefaultClient if nil.
+	Client *http.Client
+}
+
+// A PhotoManifestEntry describes a photo in the backup manifest.
+type PhotoManifestEntry struct {
+	ID      int       `json:"id"`
+	AlbumID int       `json:"album_id"`
+	OwnerID int       `json:"owner_id"`
+	Text    string    `json:"text"`
+	Date    time.Time `json:"date"`
+	Likes   int       `json:"likes"`
+	// Path of the file relative to the album directory.
+	File string `json:"file"`
+	// Set if VK gives no copy of the photo, File is empty then.
+	Unavailable bool `json:"unavailable"`
+}
+
+// An AlbumManifest describes a backed up album,
+// it's written to manifest.json and manifest.csv of the album.
+type AlbumManifest struct {
+	Album     PhotoAlbumObject     `json:"album"`
+	UpdatedAt time.Time            `json:"updated_at"`
+	Photos    []PhotoManifestEntry `json:"photos"`
+}
+
+// An AlbumBackupReport describes a result of BackupAlbums.
+type AlbumBackupReport struct {
+	Albums     int
+	Photos     int
+	Downloaded int
+	// Photos already present in the backup.
+	Skipped int
+	// Photos VK gives no copy of, the manifest marks them unavailable.
+	Unavailable int
+	// Photos which failed to download, they are retried on the next run.
+	Failed []PhotoDownload
+}
+
+// BackupAlbums mirrors albums of the owner into a local directory.
+// The largest size of every photo is saved as Dir/<album id>/<photo id>.jpg
+// along with the manifest of captions, dates and likes.
+// Photos VK gives no copy of are listed in the manifest as unavailable.
+// Photos already present are not downloaded again,
+// so it's cheap to run the backup periodically.
+func (p *Photos) BackupAlbums(par AlbumBackupParams) (*AlbumBackupReport, error) {
+	perSecond := par.RequestsPerSecond
+	if perSecond <= 0 {
+		perSecond = 3
+	}
+	limiter := newRateLimiter(perSecond)
+	downloader := &PhotoDownloader{Client: par.Client, Workers: par.Workers}
+
+	limiter.Wait()
+	albums, err := p.GetAlbums(PhotosGetAlbumsParams{
+		OwnerID:  par.OwnerID,
+		AlbumIDs: par.AlbumIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &AlbumBackupReport{}
+	for _, album := range albums.Items {
+		err = p.backupAlbum(album, par.Dir, limiter, downloader, report)
+		if err != nil {
+			return report, fmt.Errorf("album %d: %v", album.ID, err)
+		}
+		report.Albums++
+	}
+	return report, nil
+}
+
+func (p *Photos) backupAlbum(album PhotoAlbumObject, root string, limiter *rateLimiter, downloader *PhotoDownloader, report *AlbumBackupReport) error {
+	dir := filepath.Join(root, strconv.Itoa(album.ID))
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return err
+	}
+
+	photos, err := p.albumPhotos(album, limiter)
+	if err != nil {
+		return err
+	}
+	report.Photos += len(photos)
+
+	var missing []PhotoObject
+	for _, photo := range photos {
+		if fileExists(filepath.Join(dir, photoFileName(photo))) {
+			report.Skipped++
+		} else {
+			missing = append(missing, photo)
+		}
+	}
+	results := downloader.DownloadAll(missing, func(photo *PhotoObject) string {
+		return filepath.Join(dir, photoFileName(*photo))
+	})
+	failed := map[int]bool{}
+	unavailable := map[int]bool{}
+	for _, r := range results {
+		switch r.Err {
+		case nil:
+			report.Downloaded++
+		case ErrNoPhotoSize:
+			unavailable[r.Photo.ID] = true
+			report.Unavailable++
+		default:
+			failed[r.Photo.ID] = true
+			report.Failed = append(report.Failed, r)
+		}
+	}
+
+	manifest := AlbumManifest{Album: album, UpdatedAt: time.Now()}
+	for _, photo := range photos {
+		if failed[photo.ID] {
+			continue
+		}
+		entry := PhotoManifestEntry{
+			ID:      photo.ID,
+			AlbumID: photo.AlbumID,
+			OwnerID: photo.OwnerID,
+			Text:    photo.Text,
+			Date:    photo.Date.Time,
+			Likes:   photo.Likes.Count,
+			File:    photoFileName(photo),
+		}
+		if unavailable[photo.ID] {
+			entry.File = ""
+			entry.Unavailable = true
+		}
+		manifest.Photos = append(manifest.Photos, entry)
+	}
+	err = writeManifestJSON(filepath.Join(dir, "manifest.json"), manifest)
+	if err != nil {
+		return err
+	}
+	return writeManifestCSV(filepath.Join(dir, "manifest.csv"), manifest)
+}
+
+// albumPhotos returns all photos of the album with sizes and likes.
+func (p *Photos) albumPhotos(album PhotoAlbumObject, limiter *rateLimiter) ([]PhotoObject, error) {
+	var photos []PhotoObject
+	for offset := 0; ; offset += photosPerGet {
+		limiter.Wait()
+		resp, err := p.Get(PhotosGetParams{
+			OwnerID:    album.OwnerID,
+			AlbumID:    strconv.Itoa(album.ID),
+			Extended:   true,
+			PhotoSizes: true,
+			Offset:     offset,
+			Count:      photosPerGet,
+		})
+		if err != nil {
+			return nil, err
+		}
+		photos = append(photos, resp.Items...)
+		if len(resp.Items) == 0 || offset+photosPerGet >= resp.Count {
+			return photos, nil
+		}
+	}
+}
+
+func photoFileName(photo PhotoObject) string {
+	return strconv.Itoa(photo.ID) + ".jpg"
+}
+
+func fileExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && !info.IsDir()
+}
+
+func writeManifestJSON(path string, manifest AlbumManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	err = ioutil.WriteFile(tmp, data, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func writeManifestCSV(path string, manifest AlbumManifest) error {
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(f)
+	w.Write([]string{"id", "album_id", "owner_id", "date", "likes", "file", "unavailable", "text"})
+	for _, e := range manifest.Photos {
+		w.Write([]string{
+			strconv.Itoa(e.ID),
+			strconv.Itoa(e.AlbumID),
+			strconv.Itoa(e.OwnerID),
+			e.Date.Format(time.RFC3339),
+			strconv.Itoa(e.Likes),
+			e.File,
+			strconv.FormatBool(e.Unavailable),
+			e.Text,
+		})
+	}
+	w.Flush()
+	err = w.Error()
+	closeErr := f.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,173 @@
+package easyvk
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// backupServer answers photos.getAlbums and photos.get for album 1
+// of owner -5 and serves the photos. Photo 10 is already backed up,
+// photo 12 fails to download while broken is set and photo 13 has no sizes.
+type backupServer struct {
+	*httptest.Server
+	mu      sync.Mutex
+	broken  bool
+	fetched []string
+}
+
+func newBackupServer(t *testing.T) *backupServer {
+	s := &backupServer{broken: true}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/photos.getAlbums":
+			if r.FormValue("owner_id") != "-5" || r.FormValue("album_ids") != "1" {
+				t.Errorf("photos.getAlbums: got %v", r.Form)
+			}
+			fmt.Fprint(w, `{"response": {"count": 1, "items": [{"id": 1, "owner_id": -5, "title": "Album", "size": 4}]}}`)
+		case "/photos.get":
+			if r.FormValue("album_id") != "1" || r.FormValue("photo_sizes") != "1" {
+				t.Errorf("photos.get: got %v", r.Form)
+			}
+			photo := func(id int) string {
+				return fmt.Sprintf(`{"id": %d, "album_id": 1, "owner_id": -5, "date": 1700000000, "text": "photo %d", "likes": {"count": %d},
+					"sizes": [{"type": "x", "url": "%s/img/%d.jpg", "width": 604, "height": 403}]}`, id, id, id, s.URL, id)
+			}
+			fmt.Fprintf(w, `{"response": {"count": 4, "items": [%s, %s, %s, {"id": 13, "album_id": 1, "owner_id": -5, "sizes": []}]}}`,
+				photo(10), photo(11), photo(12))
+		default:
+			s.mu.Lock()
+			s.fetched = append(s.fetched, r.URL.Path)
+			broken := s.broken
+			s.mu.Unlock()
+			if r.URL.Path == "/img/12.jpg" && broken {
+				http.Error(w, "broken", http.StatusInternalServerError)
+				return
+			}
+			fmt.Fprint(w, "jpeg "+r.URL.Path)
+		}
+	}))
+	return s
+}
+
+func readManifest(t *testing.T, dir string) (AlbumManifest, [][]string) {
+	t.Helper()
+	data, err := ioutil.ReadFile(filepath.Join(dir, "manifest.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var manifest AlbumManifest
+	err = json.Unmarshal(data, &manifest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(filepath.Join(dir, "manifest.csv"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return manifest, rows
+}
+
+func TestBackupAlbums(t *testing.T) {
+	srv := newBackupServer(t)
+	defer srv.Close()
+	vk := WithToken("token")
+	vk.ApiUrl = srv.URL + "/"
+
+	root, err := ioutil.TempDir("", "backup")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(root)
+	dir := filepath.Join(root, "1")
+	err = os.MkdirAll(dir, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = ioutil.WriteFile(filepath.Join(dir, "10.jpg"), []byte("old"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	par := AlbumBackupParams{OwnerID: -5, Dir: root, AlbumIDs: []int{1}, RequestsPerSecond: 100}
+	report, err := vk.Photos.BackupAlbums(par)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Albums != 1 || report.Photos != 4 || report.Skipped != 1 || report.Downloaded != 1 || report.Unavailable != 1 {
+		t.Errorf("got report %+v", report)
+	}
+	if len(report.Failed) != 1 || report.Failed[0].Photo.ID != 12 {
+		t.Errorf("got failed %+v", report.Failed)
+	}
+	if data, _ := ioutil.ReadFile(filepath.Join(dir, "10.jpg")); string(data) != "old" {
+		t.Errorf("backed up photo is replaced with %q", data)
+	}
+	if data, _ := ioutil.ReadFile(filepath.Join(dir, "11.jpg")); string(data) != "jpeg /img/11.jpg" {
+		t.Errorf("got photo %q", data)
+	}
+	if fileExists(filepath.Join(dir, "12.jpg")) || fileExists(filepath.Join(dir, "12.jpg.tmp")) {
+		t.Error("failed photo is saved")
+	}
+
+	manifest, rows := readManifest(t, dir)
+	if manifest.Album.ID != 1 || manifest.Album.Title != "Album" {
+		t.Errorf("got album %+v", manifest.Album)
+	}
+	var ids []int
+	for _, e := range manifest.Photos {
+		ids = append(ids, e.ID)
+	}
+	if !reflect.DeepEqual(ids, []int{10, 11, 13}) {
+		t.Errorf("got photos %v in the manifest", ids)
+	}
+	if e := manifest.Photos[1]; e.Text != "photo 11" || e.Likes != 11 || e.File != "11.jpg" || e.Date.Unix() != 1700000000 || e.Unavailable {
+		t.Errorf("got entry %+v", e)
+	}
+	if e := manifest.Photos[2]; e.File != "" || !e.Unavailable {
+		t.Errorf("got entry %+v", e)
+	}
+	want := [][]string{
+		{"id", "album_id", "owner_id", "date", "likes", "file", "unavailable", "text"},
+		{"10", "1", "-5", manifest.Photos[0].Date.Format(time.RFC3339), "10", "10.jpg", "false", "photo 10"},
+		{"11", "1", "-5", manifest.Photos[1].Date.Format(time.RFC3339), "11", "11.jpg", "false", "photo 11"},
+		{"13", "1", "-5", manifest.Photos[2].Date.Format(time.RFC3339), "0", "", "true", ""},
+	}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("got csv %v, want %v", rows, want)
+	}
+
+	// the next run retries only the failed photo
+	srv.mu.Lock()
+	srv.broken = false
+	srv.fetched = nil
+	srv.mu.Unlock()
+	report, err = vk.Photos.BackupAlbums(par)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Skipped != 2 || report.Downloaded != 1 || report.Unavailable != 1 || len(report.Failed) != 0 {
+		t.Errorf("got report %+v", report)
+	}
+	if !reflect.DeepEqual(srv.fetched, []string{"/img/12.jpg"}) {
+		t.Errorf("got downloads %v", srv.fetched)
+	}
+	manifest, _ = readManifest(t, dir)
+	if len(manifest.Photos) != 4 || manifest.Photos[2].File != "12.jpg" {
+		t.Errorf("got manifest %+v", manifest.Photos)
+	}
+}
@@ -0,0 +1,339 @@
+package easyvk
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/html"
+)
+
+// maxAuthSteps limits the number of pages
+// the login flow goes through before giving up.
+const maxAuthSteps = 10
+
+// AuthErrorKind is a reason why signing in has failed.
+type AuthErrorKind int
+
+const (
+	// AuthUnknown - VK returned a page the login flow doesn't know
+	AuthUnknown AuthErrorKind = iota
+	// AuthBadCredentials - wrong login or password
+	AuthBadCredentials
+	// AuthBlocked - the account is blocked
+	AuthBlocked
+	// AuthScopeDenied - access to the requested scope is denied
+	AuthScopeDenied
+	// AuthTwoFactorRequired - VK asks for a code, but AuthParams.TwoFactor is not set
+	AuthTwoFactorRequired
+	// AuthBadCode - VK didn't accept the two-factor code
+	AuthBadCode
+	// AuthCaptchaRequired - VK asks for a captcha, but AuthParams.Captcha is not set
+	AuthCaptchaRequired
+)
+
+// An AuthError describes why signing in has failed.
+type AuthError struct {
+	Kind AuthErrorKind
+	// Message from VK if there is one.
+	Message string
+}
+
+func (e *AuthError) Error() string {
+	var reason string
+	switch e.Kind {
+	case AuthBadCredentials:
+		reason = "wrong login or password"
+	case AuthBlocked:
+		reason = "account is blocked"
+	case AuthScopeDenied:
+		reason = "access denied"
+	case AuthTwoFactorRequired:
+		reason = "two-factor code required"
+	case AuthBadCode:
+		reason = "wrong two-factor code"
+	case AuthCaptchaRequired:
+		reason = "captcha required"
+	default:
+		reason = "can't log in"
+	}
+	if e.Message != "" {
+		return reason + ": " + e.Message
+	}
+	return reason
+}
+
+// AuthParams provides fields for WithAuthParams.
+type AuthParams struct {
+	Login    string
+	Password string
+	ClientID string
+	Scope    Scope
+	// TwoFactor is called when VK asks for a code
+	// from SMS or authenticator app.
+	TwoFactor func() (string, error)
+	// Captcha is called with the captcha image url
+	// and must return the text from the image.
+	Captcha func(imageURL string) (string, error)
+	// Client to make requests with, it must have a cookie jar.
+	// By default a new client is used.
+	Client *http.Client
+	// If set, the token is loaded from the store and
+	// signing in happens only if there is no valid token
+	// with all permissions of Scope.
+	// New tokens are saved to the store.
+	Store TokenStore
+}
+
+// WithAuth helps to initialize your VK object
+// with signing in by login, password, client id and scope
+func WithAuth(login, password, clientID string, scope Scope) (*VK, error) {
+	return WithAuthParams(AuthParams{
+		Login:    login,
+		Password: password,
+		ClientID: clientID,
+		Scope:    scope,
+	})
+}
+
+// WithAuthParams helps to initialize your VK object
+// with signing in, it passes through two-factor, captcha
+// and permissions pages. Returns *AuthError if VK refuses to sign in.
+func WithAuthParams(p AuthParams) (*VK, error) {
+	key := p.ClientID + ":" + p.Login
+	if p.Store != nil {
+		vk, err := LoadToken(p.Store, key)
+		if err != nil {
+			return nil, err
+		}
+		// the stored token may be issued for fewer permissions
+		if vk != nil && vk.Scope.Has(p.Scope) {
+			return vk, nil
+		}
+	}
+
+	token, err := signIn(p)
+	if err != nil {
+		return nil, err
+	}
+	if p.Store != nil {
+		err = p.Store.Save(key, token)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return token.VK(), nil
+}
+
+// signIn passes through the login pages
+// and returns the token from the final redirect.
+func signIn(p AuthParams) (*StoredToken, error) {
+	client := p.Client
+	if client == nil {
+		jar, _ := cookiejar.New(nil)
+		client = &http.Client{
+			Jar: jar,
+		}
+	}
+
+	resp, err := client.Get(fmt.Sprintf(authURL, p.ClientID, uint(p.Scope), version))
+	if err != nil {
+		return nil, err
+	}
+
+	credentialsSent := false
+	codeSent := false
+	for step := 0; step < maxAuthSteps; step++ {
+		location := resp.Request.URL
+		if location.Path == "/blank.html" {
+			resp.Body.Close()
+			token, err := tokenFromRedirect(location)
+			if err != nil {
+				return nil, err
+			}
+			token.Kind = UserToken
+			token.Scope = p.Scope
+			return token, nil
+		}
+		if strings.Contains(location.Path, "blocked") || location.Query().Get("act") == "blocked" {
+			resp.Body.Close()
+			return nil, &AuthError{Kind: AuthBlocked}
+		}
+
+		page := parseAuthPage(resp.Body)
+		resp.Body.Close()
+		if page.action == "" {
+			return nil, &AuthError{Kind: AuthUnknown, Message: page.warning}
+		}
+
+		if page.has("captcha_sid") {
+			if p.Captcha == nil {
+				return nil, &AuthError{Kind: AuthCaptchaRequired, Message: page.captcha}
+			}
+			img, err := location.Parse(page.captcha)
+			if err != nil {
+				return nil, err
+			}
+			key, err := p.Captcha(img.String())
+			if err != nil {
+				return nil, err
+			}
+			page.values.Set("captcha_key", key)
+		}
+
+		switch {
+		case page.has("pass"):
+			// login form again means that credentials are wrong,
+			// unless VK has just asked for a captcha
+			if credentialsSent && !page.has("captcha_sid") {
+				return nil, &AuthError{Kind: AuthBadCredentials, Message: page.warning}
+			}
+			page.values.Set("email", p.Login)
+			page.values.Set("pass", p.Password)
+			credentialsSent = true
+		case page.has("code"):
+			if p.TwoFactor == nil {
+				return nil, &AuthError{Kind: AuthTwoFactorRequired, Message: page.warning}
+			}
+			if codeSent && !page.has("captcha_sid") {
+				return nil, &AuthError{Kind: AuthBadCode, Message: page.warning}
+			}
+			code, err := p.TwoFactor()
+			if err != nil {
+				return nil, err
+			}
+			page.values.Set("code", code)
+			page.values.Set("remember", "1")
+			codeSent = true
+		default:
+			// permissions page, submitting it grants the access
+		}
+
+		action, err := location.Parse(page.action)
+		if err != nil {
+			return nil, err
+		}
+		resp, err = client.PostForm(action.String(), page.values)
+		if err != nil {
+			return nil, err
+		}
+	}
+	resp.Body.Close()
+
+	return nil, &AuthError{Kind: AuthUnknown, Message: "too many steps"}
+}
+
+// tokenFromRedirect returns the token
+// from the fragment of the redirect url.
+func tokenFromRedirect(u *url.URL) (*StoredToken, error) {
+	args, err := url.ParseQuery(u.Fragment)
+	if err != nil {
+		return nil, err
+	}
+	if e := args.Get("error"); e != "" {
+		kind := AuthUnknown
+		if e == "access_denied" {
+			kind = AuthScopeDenied
+		}
+		return nil, &AuthError{Kind: kind, Message: args.Get("error_description")}
+	}
+	token := &StoredToken{AccessToken: args.Get("access_token")}
+	if token.AccessToken == "" {
+		return nil, &AuthError{Kind: AuthUnknown, Message: "no access token"}
+	}
+	token.UserID, _ = strconv.Atoi(args.Get("user_id"))
+	if expiresIn, _ := strconv.Atoi(args.Get("expires_in")); expiresIn > 0 {
+		token.ExpiresAt = time.Now().Add(time.Duration(expiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// An authPage describes the first form on the login page.
+type authPage struct {
+	action string
+	values url.Values
+	// captcha image url
+	captcha string
+	// text of the warning message
+	warning string
+}
+
+func (p *authPage) has(name string) bool {
+	_, ok := p.values[name]
+	return ok
+}
+
+func attr(token html.Token, key string) (string, bool) {
+	for _, a := range token.Attr {
+		if a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
+
+func parseAuthPage(body io.Reader) *authPage {
+	page := &authPage{values: url.Values{}}
+	tokenizer := html.NewTokenizer(body)
+
+	inForm := false
+	formDone := false
+	inWarning := false
+	for {
+		tag := tokenizer.Next()
+		if tag == html.ErrorToken {
+			return page
+		}
+		token := tokenizer.Token()
+
+		switch tag {
+		case html.StartTagToken, html.SelfClosingTagToken:
+			switch token.Data {
+			case "form":
+				if !formDone {
+					page.action, _ = attr(token, "action")
+					inForm = true
+				}
+			case "input":
+				name, ok := attr(token, "name")
+				if !inForm || !ok {
+					continue
+				}
+				if typ, _ := attr(token, "type"); typ == "submit" {
+					continue
+				}
+				value, _ := attr(token, "value")
+				page.values.Set(name, value)
+			case "img":
+				src, _ := attr(token, "src")
+				if strings.Contains(src, "captcha") {
+					page.captcha = src
+				}
+			case "div":
+				class, _ := attr(token, "class")
+				if strings.Contains(class, "service_msg") {
+					inWarning = true
+				}
+			}
+		case html.EndTagToken:
+			switch token.Data {
+			case "form":
+				if inForm {
+					inForm = false
+					formDone = true
+				}
+			case "div":
+				inWarning = false
+			}
+		case html.TextToken:
+			if inWarning {
+				page.warning += strings.TrimSpace(token.Data)
+			}
+		}
+	}
+}
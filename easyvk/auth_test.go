@@ -0,0 +1,255 @@
+package easyvk
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// authServer imitates the login pages of VK. If code or captcha
+// are set, VK asks for them, blocked and deny end the flow.
+type authServer struct {
+	password string
+	code     string
+	captcha  string
+	blocked  bool
+	deny     bool
+}
+
+func (s *authServer) loginForm(w http.ResponseWriter, warning string, captcha bool) {
+	fmt.Fprint(w, `<html><body>`)
+	if warning != "" {
+		fmt.Fprintf(w, `<div class="service_msg service_msg_warning">%s</div>`, warning)
+	}
+	fmt.Fprint(w, `<form method="post" action="/login?act=login">
+<input type="hidden" name="ip_h" value="1a2b">
+<input type="text" name="email">
+<input type="password" name="pass">`)
+	if captcha {
+		fmt.Fprint(w, `<img src="/captcha.php?sid=42"><input type="hidden" name="captcha_sid" value="42"><input type="text" name="captcha_key">`)
+	}
+	fmt.Fprint(w, `<input type="submit" value="Log in"></form></body></html>`)
+}
+
+func (s *authServer) codeForm(w http.ResponseWriter, warning string) {
+	fmt.Fprint(w, `<html><body>`)
+	if warning != "" {
+		fmt.Fprintf(w, `<div class="service_msg service_msg_warning">%s</div>`, warning)
+	}
+	fmt.Fprint(w, `<form method="post" action="/code?act=authcheck_code">
+<input type="text" name="code"><input type="submit" value="Confirm"></form></body></html>`)
+}
+
+func (s *authServer) grantForm(w http.ResponseWriter) {
+	fmt.Fprint(w, `<html><body><form method="post" action="/grant?hash=abc">
+<input type="submit" value="Allow"></form>
+<form method="post" action="/cancel"><input type="hidden" name="other" value="1"></form></body></html>`)
+}
+
+func (s *authServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.URL.Path {
+	case "/authorize":
+		s.loginForm(w, "", false)
+	case "/login":
+		if r.FormValue("email") != "user@example.com" {
+			http.Error(w, "no login", http.StatusBadRequest)
+			return
+		}
+		if s.captcha != "" && r.FormValue("captcha_key") != s.captcha {
+			s.loginForm(w, "", true)
+			return
+		}
+		if r.FormValue("pass") != s.password {
+			s.loginForm(w, "Incorrect login or password", false)
+			return
+		}
+		if s.blocked {
+			http.Redirect(w, r, "/blocked", http.StatusFound)
+			return
+		}
+		if s.code != "" {
+			s.codeForm(w, "")
+			return
+		}
+		s.grantForm(w)
+	case "/code":
+		if r.FormValue("code") != s.code {
+			s.codeForm(w, "Incorrect code")
+			return
+		}
+		s.grantForm(w)
+	case "/grant":
+		fragment := "access_token=token&expires_in=86400&user_id=7"
+		if s.deny {
+			fragment = "error=access_denied&error_description=User+denied+your+request"
+		}
+		http.Redirect(w, r, "https://oauth.vk.com/blank.html#"+fragment, http.StatusFound)
+	case "/blank.html", "/blocked":
+		fmt.Fprint(w, `<html></html>`)
+	default:
+		fmt.Fprint(w, `<html><body>Something went wrong</body></html>`)
+	}
+}
+
+// hostTransport sends all requests to the test server
+// but keeps the original url in the response.
+type hostTransport struct {
+	host string
+}
+
+func (t hostTransport) RoundTrip(r *http.Request) (*http.Response, error) {
+	out := r.Clone(r.Context())
+	out.URL.Scheme = "http"
+	out.URL.Host = t.host
+	resp, err := http.DefaultTransport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	resp.Request = r
+	return resp, nil
+}
+
+func TestSignIn(t *testing.T) {
+	tests := []struct {
+		name      string
+		server    authServer
+		password  string
+		twoFactor string
+		captcha   string
+		kind      AuthErrorKind
+		message   string
+	}{
+		{"ok", authServer{password: "pass"}, "pass", "", "", -1, ""},
+		{"two-factor", authServer{password: "pass", code: "123456"}, "pass", "123456", "", -1, ""},
+		{"captcha", authServer{password: "pass", captcha: "abc"}, "pass", "", "abc", -1, ""},
+		{"captcha and two-factor", authServer{password: "pass", code: "123456", captcha: "abc"}, "pass", "123456", "abc", -1, ""},
+		{"bad credentials", authServer{password: "pass"}, "wrong", "", "", AuthBadCredentials, "Incorrect login or password"},
+		{"two-factor required", authServer{password: "pass", code: "123456"}, "pass", "", "", AuthTwoFactorRequired, ""},
+		{"bad code", authServer{password: "pass", code: "123456"}, "pass", "000000", "", AuthBadCode, "Incorrect code"},
+		{"captcha required", authServer{password: "pass", captcha: "abc"}, "pass", "", "", AuthCaptchaRequired, "/captcha.php?sid=42"},
+		{"blocked", authServer{password: "pass", blocked: true}, "pass", "", "", AuthBlocked, ""},
+		{"scope denied", authServer{password: "pass", deny: true}, "pass", "", "", AuthScopeDenied, "User denied your request"},
+	}
+	for _, tt := range tests {
+		server := tt.server
+		srv := httptest.NewServer(&server)
+		jar, _ := cookiejar.New(nil)
+		p := AuthParams{
+			Login:    "user@example.com",
+			Password: tt.password,
+			ClientID: "1",
+			Scope:    ScopeWall,
+			Client:   &http.Client{Jar: jar, Transport: hostTransport{strings.TrimPrefix(srv.URL, "http://")}},
+		}
+		if tt.twoFactor != "" {
+			p.TwoFactor = func() (string, error) {
+				return tt.twoFactor, nil
+			}
+		}
+		if tt.captcha != "" {
+			p.Captcha = func(imageURL string) (string, error) {
+				if imageURL != "https://oauth.vk.com/captcha.php?sid=42" {
+					t.Errorf("%s: got captcha %s", tt.name, imageURL)
+				}
+				return tt.captcha, nil
+			}
+		}
+
+		token, err := signIn(p)
+		srv.Close()
+
+		if tt.kind < 0 {
+			if err != nil {
+				t.Errorf("%s: %v", tt.name, err)
+				continue
+			}
+			if token.AccessToken != "token" || token.UserID != 7 || token.Kind != UserToken || token.Scope != ScopeWall {
+				t.Errorf("%s: got %+v", tt.name, token)
+			}
+			if time.Until(token.ExpiresAt) < 23*time.Hour {
+				t.Errorf("%s: token expires at %s", tt.name, token.ExpiresAt)
+			}
+			continue
+		}
+		authErr, ok := err.(*AuthError)
+		if !ok {
+			t.Errorf("%s: got %v, want AuthError", tt.name, err)
+			continue
+		}
+		if authErr.Kind != tt.kind || authErr.Message != tt.message {
+			t.Errorf("%s: got %d %q, want %d %q", tt.name, authErr.Kind, authErr.Message, tt.kind, tt.message)
+		}
+	}
+}
+
+func TestSignInUnknownPage(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `<html><body><div class="service_msg">Try again later</div></body></html>`)
+	}))
+	defer srv.Close()
+
+	_, err := signIn(AuthParams{
+		Client: &http.Client{Transport: hostTransport{strings.TrimPrefix(srv.URL, "http://")}},
+	})
+	authErr, ok := err.(*AuthError)
+	if !ok || authErr.Kind != AuthUnknown || authErr.Message != "Try again later" {
+		t.Errorf("got %v, want unknown page error", err)
+	}
+}
+
+func TestTokenFromRedirect(t *testing.T) {
+	tests := []struct {
+		url    string
+		token  string
+		userID int
+		kind   AuthErrorKind
+	}{
+		{"https://oauth.vk.com/blank.html#access_token=abc&expires_in=0&user_id=1", "abc", 1, -1},
+		{"https://oauth.vk.com/blank.html#error=access_denied&error_description=denied", "", 0, AuthScopeDenied},
+		{"https://oauth.vk.com/blank.html#error=invalid_request", "", 0, AuthUnknown},
+		{"https://oauth.vk.com/blank.html#user_id=1", "", 0, AuthUnknown},
+	}
+	for _, tt := range tests {
+		u, _ := url.Parse(tt.url)
+		token, err := tokenFromRedirect(u)
+		if tt.kind < 0 {
+			if err != nil || token.AccessToken != tt.token || token.UserID != tt.userID || !token.ExpiresAt.IsZero() {
+				t.Errorf("%s: got %+v, %v", tt.url, token, err)
+			}
+			continue
+		}
+		if authErr, ok := err.(*AuthError); !ok || authErr.Kind != tt.kind {
+			t.Errorf("%s: got %v, want kind %d", tt.url, err, tt.kind)
+		}
+	}
+}
+
+func TestParseAuthPage(t *testing.T) {
+	page := parseAuthPage(strings.NewReader(`<html><body>
+<div class="service_msg service_msg_warning"> Enter the code </div>
+<form method="post" action="/login?act=login">
+<input type="hidden" name="ip_h" value="1a2b">
+<input type="text" name="email" value="">
+<img src="/captcha.php?sid=42">
+<input type="hidden" name="captcha_sid" value="42">
+<input type="submit" name="submit" value="Log in">
+</form>
+<form action="/other"><input name="other"></form>
+</body></html>`))
+
+	if page.action != "/login?act=login" {
+		t.Errorf("got action %q", page.action)
+	}
+	want := url.Values{"ip_h": {"1a2b"}, "email": {""}, "captcha_sid": {"42"}}
+	if page.values.Encode() != want.Encode() {
+		t.Errorf("got values %v, want %v", page.values, want)
+	}
+	if page.captcha != "/captcha.php?sid=42" || page.warning != "Enter the code" {
+		t.Errorf("got captcha %q and warning %q", page.captcha, page.warning)
+	}
+}
@@ -1,8 +1,10 @@
 package easyvk
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 // A Board describes a set of methods to work with topics.
@@ -60,6 +62,36 @@ func (b *Board) CloseTopic(groupID, topicID uint) (bool, error) {
 	return ok == 1, nil
 }
 
+// OpenTopic re-opens a previously closed topic on a community's discussion board.
+// https://vk.com/dev/board.openTopic
+func (b *Board) OpenTopic(groupID, topicID uint) (bool, error) {
+	params := map[string]string{
+		"group_id": fmt.Sprint(groupID),
+		"topic_id": fmt.Sprint(topicID),
+	}
+	return b.vk.requestBool("board.openTopic", params)
+}
+
+// FixTopic pins a topic to the top of a community's discussion board.
+// https://vk.com/dev/board.fixTopic
+func (b *Board) FixTopic(groupID, topicID uint) (bool, error) {
+	params := map[string]string{
+		"group_id": fmt.Sprint(groupID),
+		"topic_id": fmt.Sprint(topicID),
+	}
+	return b.vk.requestBool("board.fixTopic", params)
+}
+
+// UnfixTopic unpins a topic from the top of a community's discussion board.
+// https://vk.com/dev/board.unfixTopic
+func (b *Board) UnfixTopic(groupID, topicID uint) (bool, error) {
+	params := map[string]string{
+		"group_id": fmt.Sprint(groupID),
+		"topic_id": fmt.Sprint(topicID),
+	}
+	return b.vk.requestBool("board.unfixTopic", params)
+}
+
 // DeleteTopic deletes a topic from a community's discussion board.
 // https://vk.com/dev/board.deleteTopic
 func (b *Board) DeleteTopic(groupID, topicID uint) (bool, error) {
@@ -116,3 +148,189 @@ func (b *Board) DeleteComment(groupID, topicID, commentId int) (bool, error) {
 	}
 	return ok == 1, nil
 }
+
+// BoardGetTopicsParams provides fields for GetTopics params.
+// https://vk.com/dev/board.getTopics
+type BoardGetTopicsParams struct {
+	GroupID uint
+	// Topics to return, all topics if empty.
+	TopicIDs []uint
+	// 1 by updated desc, 2 by created desc, -1 by updated asc,
+	// -2 by created asc, 0 in the order set by the community.
+	Order  int
+	Offset int
+	Count  int
+	// Fill Profiles of topic creators and editors.
+	Extended bool
+	// 1 to fill FirstComment, 2 to fill LastComment, 3 for both.
+	Preview int
+	// Max length of preview comments, 90 if zero.
+	PreviewLength int
+}
+
+// BoardGetTopicsResponse describes a list of topics.
+// https://vk.com/dev/board.getTopics
+type BoardGetTopicsResponse struct {
+	Count        int           `json:"count"`
+	Items        []TopicObject `json:"items"`
+	DefaultOrder int           `json:"default_order"`
+	CanAddTopics BoolInt       `json:"can_add_topics"`
+	Profiles     []UserObject  `json:"profiles"`
+}
+
+// GetTopics returns a list of topics on a community's discussion board.
+// https://vk.com/dev/board.getTopics
+func (b *Board) GetTopics(p BoardGetTopicsParams) (*BoardGetTopicsResponse, error) {
+	ids := make([]string, len(p.TopicIDs))
+	for i, id := range p.TopicIDs {
+		ids[i] = fmt.Sprint(id)
+	}
+	params := map[string]string{
+		"group_id":  fmt.Sprint(p.GroupID),
+		"topic_ids": strings.Join(ids, ","),
+		"order":     strconv.Itoa(p.Order),
+		"offset":    strconv.Itoa(p.Offset),
+		"extended":  boolConverter(p.Extended),
+		"preview":   strconv.Itoa(p.Preview),
+	}
+	if p.Count != 0 {
+		params["count"] = strconv.Itoa(p.Count)
+	}
+	if p.PreviewLength != 0 {
+		params["preview_length"] = strconv.Itoa(p.PreviewLength)
+	}
+	resp, err := b.vk.Request("board.getTopics", params)
+	if err != nil {
+		return nil, err
+	}
+	var topics BoardGetTopicsResponse
+	err = json.Unmarshal(resp, &topics)
+	if err != nil {
+		return nil, err
+	}
+	return &topics, nil
+}
+
+// BoardGetCommentsParams provides fields for GetComments params.
+// https://vk.com/dev/board.getComments
+type BoardGetCommentsParams struct {
+	GroupID   int
+	TopicID   int
+	NeedLikes bool
+	// Comment to start from, Offset is counted from it.
+	StartCommentID int
+	Offset         int
+	Count          int
+	// one of: asc (default), desc
+	Sort string
+	// Fill Profiles and Groups of comment authors.
+	Extended bool
+}
+
+// BoardGetCommentsResponse describes a list of comments in the topic.
+// https://vk.com/dev/board.getComments
+type BoardGetCommentsResponse struct {
+	Count    int                  `json:"count"`
+	Items    []TopicCommentObject `json:"items"`
+	Profiles []UserObject         `json:"profiles"`
+	Groups   []GroupObject        `json:"groups"`
+}
+
+// GetComments returns a list of comments in the topic.
+// https://vk.com/dev/board.getComments
+func (b *Board) GetComments(p BoardGetCommentsParams) (*BoardGetCommentsResponse, error) {
+	params := map[string]string{
+		"group_id":   fmt.Sprint(p.GroupID),
+		"topic_id":   fmt.Sprint(p.TopicID),
+		"need_likes": boolConverter(p.NeedLikes),
+		"offset":     strconv.Itoa(p.Offset),
+		"sort":       p.Sort,
+		"extended":   boolConverter(p.Extended),
+	}
+	if p.StartCommentID != 0 {
+		params["start_comment_id"] = strconv.Itoa(p.StartCommentID)
+	}
+	if p.Count != 0 {
+		params["count"] = strconv.Itoa(p.Count)
+	}
+	resp, err := b.vk.Request("board.getComments", params)
+	if err != nil {
+		return nil, err
+	}
+	var comments BoardGetCommentsResponse
+	err = json.Unmarshal(resp, &comments)
+	if err != nil {
+		return nil, err
+	}
+	return &comments, nil
+}
+
+// BoardCreateCommentParams provides fields for CreateComment params.
+// https://vk.com/dev/board.createComment
+type BoardCreateCommentParams struct {
+	GroupID int
+	TopicID int
+	Message string
+	// comma separated list like photo100172_166443618
+	Attachments string
+	FromGroup   bool
+	StickerID   int
+	GUID        string
+}
+
+// CreateComment adds a new comment in the topic
+// and returns ID of the comment.
+// https://vk.com/dev/board.createComment
+func (b *Board) CreateComment(p BoardCreateCommentParams) (int, error) {
+	params := map[string]string{
+		"group_id":    fmt.Sprint(p.GroupID),
+		"topic_id":    fmt.Sprint(p.TopicID),
+		"message":     p.Message,
+		"attachments": p.Attachments,
+		"from_group":  boolConverter(p.FromGroup),
+		"guid":        p.GUID,
+	}
+	if p.StickerID != 0 {
+		params["sticker_id"] = strconv.Itoa(p.StickerID)
+	}
+	resp, err := b.vk.Request("board.createComment", params)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(string(resp))
+}
+
+// BoardEditCommentParams provides fields for EditComment params.
+// https://vk.com/dev/board.editComment
+type BoardEditCommentParams struct {
+	GroupID   int
+	TopicID   int
+	CommentID int
+	Message   string
+	// comma separated list like photo100172_166443618
+	Attachments string
+}
+
+// EditComment edits a comment in the topic.
+// https://vk.com/dev/board.editComment
+func (b *Board) EditComment(p BoardEditCommentParams) (bool, error) {
+	params := map[string]string{
+		"group_id":    fmt.Sprint(p.GroupID),
+		"topic_id":    fmt.Sprint(p.TopicID),
+		"comment_id":  fmt.Sprint(p.CommentID),
+		"message":     p.Message,
+		"attachments": p.Attachments,
+	}
+	return b.vk.requestBool("board.editComment", params)
+}
+
+// RestoreComment restores a deleted comment in the topic.
+// https://vk.com/dev/board.restoreComment
+func (b *Board) RestoreComment(groupID, topicID, commentID int) (bool, error) {
+	params := map[string]string{
+		"group_id":   fmt.Sprint(groupID),
+		"topic_id":   fmt.Sprint(topicID),
+		"comment_id": fmt.Sprint(commentID),
+	}
+	return b.vk.requestBool("board.restoreComment", params)
+}
@@ -0,0 +1,128 @@
+package easyvk
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+)
+
+// A CallbackEvent describes an event
+// sent to the server by Callback API.
+// https://vk.com/dev/callback_api
+type CallbackEvent struct {
+	Type    CallbackEventType `json:"type"`
+	Object  json.RawMessage   `json:"object"`
+	GroupID int               `json:"group_id"`
+	EventID string            `json:"event_id"`
+	Secret  string            `json:"secret"`
+}
+
+// CallbackEventType is a type of the Callback API event.
+// https://vk.com/dev/groups_events
+type CallbackEventType string
+
+const (
+	ConfirmationEvent CallbackEventType = "confirmation"
+
+	MessageNewEvent         CallbackEventType = "message_new"
+	MessageReplyEvent       CallbackEventType = "message_reply"
+	MessageEditEvent        CallbackEventType = "message_edit"
+	MessageAllowEvent       CallbackEventType = "message_allow"
+	MessageDenyEvent        CallbackEventType = "message_deny"
+	MessageTypingStateEvent CallbackEventType = "message_typing_state"
+	MessageEventEvent       CallbackEventType = "message_event"
+
+	PhotoNewEvent            CallbackEventType = "photo_new"
+	PhotoCommentNewEvent     CallbackEventType = "photo_comment_new"
+	PhotoCommentEditEvent    CallbackEventType = "photo_comment_edit"
+	PhotoCommentDeleteEvent  CallbackEventType = "photo_comment_delete"
+	PhotoCommentRestoreEvent CallbackEventType = "photo_comment_restore"
+
+	AudioNewEvent CallbackEventType = "audio_new"
+
+	VideoNewEvent            CallbackEventType = "video_new"
+	VideoCommentNewEvent     CallbackEventType = "video_comment_new"
+	VideoCommentEditEvent    CallbackEventType = "video_comment_edit"
+	VideoCommentDeleteEvent  CallbackEventType = "video_comment_delete"
+	VideoCommentRestoreEvent CallbackEventType = "video_comment_restore"
+
+	WallPostNewEvent      CallbackEventType = "wall_post_new"
+	WallRepostEvent       CallbackEventType = "wall_repost"
+	WallReplyNewEvent     CallbackEventType = "wall_reply_new"
+	WallReplyEditEvent    CallbackEventType = "wall_reply_edit"
+	WallReplyDeleteEvent  CallbackEventType = "wall_reply_delete"
+	WallReplyRestoreEvent CallbackEventType = "wall_reply_restore"
+	LikeAddEvent          CallbackEventType = "like_add"
+	LikeRemoveEvent       CallbackEventType = "like_remove"
+	BoardPostNewEvent     CallbackEventType = "board_post_new"
+	BoardPostEditEvent    CallbackEventType = "board_post_edit"
+	BoardPostDeleteEvent  CallbackEventType = "board_post_delete"
+	BoardPostRestoreEvent CallbackEventType = "board_post_restore"
+
+	MarketCommentNewEvent     CallbackEventType = "market_comment_new"
+	MarketCommentEditEvent    CallbackEventType = "market_comment_edit"
+	MarketCommentDeleteEvent  CallbackEventType = "market_comment_delete"
+	MarketCommentRestoreEvent CallbackEventType = "market_comment_restore"
+	MarketOrderNewEvent       CallbackEventType = "market_order_new"
+	MarketOrderEditEvent      CallbackEventType = "market_order_edit"
+
+	GroupJoinEvent           CallbackEventType = "group_join"
+	GroupLeaveEvent          CallbackEventType = "group_leave"
+	UserBlockEvent           CallbackEventType = "user_block"
+	UserUnblockEvent         CallbackEventType = "user_unblock"
+	PollVoteNewEvent         CallbackEventType = "poll_vote_new"
+	GroupOfficersEditEvent   CallbackEventType = "group_officers_edit"
+	GroupChangeSettingsEvent CallbackEventType = "group_change_settings"
+	GroupChangePhotoEvent    CallbackEventType = "group_change_photo"
+	VKPayTransactionEvent    CallbackEventType = "vkpay_transaction"
+	AppPayloadEvent          CallbackEventType = "app_payload"
+	LeadFormsNewEvent        CallbackEventType = "lead_forms_new"
+
+	DonutSubscriptionCreateEvent       CallbackEventType = "donut_subscription_create"
+	DonutSubscriptionProlongedEvent    CallbackEventType = "donut_subscription_prolonged"
+	DonutSubscriptionExpiredEvent      CallbackEventType = "donut_subscription_expired"
+	DonutSubscriptionCancelledEvent    CallbackEventType = "donut_subscription_cancelled"
+	DonutSubscriptionPriceChangedEvent CallbackEventType = "donut_subscription_price_changed"
+	DonutMoneyWithdrawEvent            CallbackEventType = "donut_money_withdraw"
+	DonutMoneyWithdrawErrorEvent       CallbackEventType = "donut_money_withdraw_error"
+)
+
+// A CallbackHandler describes an http.Handler
+// which receives Callback API requests.
+// https://vk.com/dev/callback_api
+type CallbackHandler struct {
+	// Code returned by groups.getCallbackConfirmationCode.
+	ConfirmationCode string
+	// Secret key of the server, requests with
+	// another secret are rejected. Empty to skip the check.
+	SecretKey string
+	// Handle is called for every event except confirmation.
+	// VK sends the event again if it doesn't get "ok" in a few
+	// seconds, so slow work should be done in another goroutine.
+	Handle func(e CallbackEvent)
+}
+
+// ServeHTTP answers the confirmation request and
+// passes other events to Handle.
+func (h *CallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var e CallbackEvent
+	err := json.NewDecoder(r.Body).Decode(&e)
+	if err != nil {
+		http.Error(w, "bad request", http.StatusBadRequest)
+		return
+	}
+	if h.SecretKey != "" && e.Secret != h.SecretKey {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	if e.Type == ConfirmationEvent {
+		io.WriteString(w, h.ConfirmationCode)
+		return
+	}
+
+	if h.Handle != nil {
+		h.Handle(e)
+	}
+	io.WriteString(w, "ok")
+}
@@ -0,0 +1,140 @@
+package easyvk
+
+import (
+	"fmt"
+	"time"
+)
+
+// Statuses of the Callback API server.
+const (
+	CallbackServerUnconfigured = "unconfigured"
+	CallbackServerFail         = "fail"
+	CallbackServerWait         = "wait"
+	CallbackServerOk           = "ok"
+)
+
+// EnsureCallbackServerParams describes the desired
+// state of the Callback API server.
+type EnsureCallbackServerParams struct {
+	GroupId int
+	// If set, this server is edited,
+	// otherwise the server is found by Url.
+	ServerId  int
+	Url       string
+	Title     string
+	SecretKey string
+	// Events to enable, all other events are disabled.
+	Events []CallbackEventType
+	// Leave empty to keep the current version.
+	APIVersion string
+	// How long to wait for the confirmation, 1 minute by default.
+	Timeout time.Duration
+	// How often to check the server status, 2 seconds by default.
+	PollInterval time.Duration
+}
+
+// A CallbackServerError describes why
+// the server has not been confirmed.
+type CallbackServerError struct {
+	ServerId int
+	Status   string
+	Reason   string
+}
+
+func (e *CallbackServerError) Error() string {
+	return fmt.Sprintf("callback server %d (%s): %s", e.ServerId, e.Status, e.Reason)
+}
+
+// EnsureCallbackServer converges the Callback API server of the community to p.
+// The server is found by p.ServerId or by url, then it is added or edited if needed,
+// its events are set to p.Events and the status is checked until
+// the server is confirmed. It is safe to call it several times.
+// Returns *CallbackServerError if confirmation fails or takes too long.
+func (g *Groups) EnsureCallbackServer(p EnsureCallbackServerParams) (*CallbackServer, error) {
+	timeout := time.Minute
+	if p.Timeout != 0 {
+		timeout = p.Timeout
+	}
+	interval := 2 * time.Second
+	if p.PollInterval != 0 {
+		interval = p.PollInterval
+	}
+
+	servers, err := g.GetCallbackServers(p.GroupId, nil)
+	if err != nil {
+		return nil, err
+	}
+	var server *CallbackServer
+	for i := range servers.Items {
+		found := servers.Items[i].Url == p.Url
+		if p.ServerId != 0 {
+			found = servers.Items[i].ID == p.ServerId
+		}
+		if found {
+			server = &servers.Items[i]
+			break
+		}
+	}
+	if server == nil && p.ServerId != 0 {
+		return nil, &CallbackServerError{p.ServerId, "", "server not found"}
+	}
+
+	if server == nil {
+		added, err := g.AddCallbackServer(p.GroupId, p.Url, p.Title, p.SecretKey)
+		if err != nil {
+			return nil, err
+		}
+		server = &CallbackServer{ID: added.ServerId, Status: CallbackServerWait}
+	} else if server.Url != p.Url || server.Title != p.Title ||
+		server.SecretKey != p.SecretKey || server.Status == CallbackServerFail {
+		// editing also makes VK send the confirmation request again
+		_, err := g.EditCallbackServer(p.GroupId, server.ID, p.Url, p.Title, p.SecretKey)
+		if err != nil {
+			return nil, err
+		}
+		server.Status = CallbackServerWait
+	}
+
+	_, err = g.UpdateCallbackSettings(p.GroupId, server.ID, func(s *CallbackSettings) {
+		if p.APIVersion != "" {
+			s.APIVersion = p.APIVersion
+		}
+		for event := range s.Events {
+			s.Events[event] = false
+		}
+		for _, event := range p.Events {
+			s.Events[event] = true
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for {
+		servers, err := g.GetCallbackServers(p.GroupId, []int{server.ID})
+		if err != nil {
+			return nil, err
+		}
+		if len(servers.Items) == 0 {
+			return nil, &CallbackServerError{server.ID, "", "server has been removed"}
+		}
+		server = &servers.Items[0]
+
+		switch server.Status {
+		case CallbackServerOk:
+			return server, nil
+		case CallbackServerFail:
+			return nil, &CallbackServerError{server.ID, server.Status,
+				"confirmation failed, the url did not return the confirmation code"}
+		case CallbackServerUnconfigured:
+			return nil, &CallbackServerError{server.ID, server.Status, "url is not set"}
+		}
+
+		if time.Now().After(deadline) {
+			return nil, &CallbackServerError{server.ID, server.Status,
+				"timed out waiting for the confirmation"}
+		}
+		time.Sleep(interval)
+	}
+}
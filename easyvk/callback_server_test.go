@@ -0,0 +1,178 @@
+package easyvk
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// callbackAPI imitates Callback API servers of a community,
+// added and edited servers are confirmed at once.
+type callbackAPI struct {
+	mu      sync.Mutex
+	servers []CallbackServer
+	events  map[string]int
+	calls   []string
+}
+
+func (a *callbackAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	method := strings.TrimPrefix(r.URL.Path, "/")
+	if method != "groups.getCallbackServers" && method != "groups.getCallbackSettings" {
+		a.calls = append(a.calls, method)
+	}
+
+	var response interface{} = 1
+	switch method {
+	case "groups.getCallbackServers":
+		items := []CallbackServer{}
+		for _, s := range a.servers {
+			if ids := r.FormValue("server_ids"); ids == "" || ids == strconv.Itoa(s.ID) {
+				items = append(items, s)
+			}
+		}
+		response = GetCallbackServersResponse{len(items), items}
+	case "groups.addCallbackServer":
+		id := 10 + len(a.servers)
+		a.servers = append(a.servers, CallbackServer{
+			ID:        id,
+			Url:       r.FormValue("url"),
+			Title:     r.FormValue("title"),
+			SecretKey: r.FormValue("secret_key"),
+			Status:    CallbackServerOk,
+		})
+		response = map[string]int{"server_id": id}
+	case "groups.editCallbackServer":
+		for i := range a.servers {
+			if strconv.Itoa(a.servers[i].ID) == r.FormValue("server_id") {
+				a.servers[i].Url = r.FormValue("url")
+				a.servers[i].Title = r.FormValue("title")
+				a.servers[i].SecretKey = r.FormValue("secret_key")
+				a.servers[i].Status = CallbackServerOk
+			}
+		}
+	case "groups.getCallbackSettings":
+		response = map[string]interface{}{"api_version": "5.63", "events": a.events}
+	case "groups.setCallbackSettings":
+		for event := range a.events {
+			if v := r.FormValue(event); v != "" {
+				a.events[event], _ = strconv.Atoi(v)
+			}
+		}
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"response": response})
+}
+
+func TestEnsureCallbackServer(t *testing.T) {
+	bot := CallbackServer{ID: 1, Title: "bot", Url: "https://bot.example.com/vk", SecretKey: "s", Status: CallbackServerOk}
+	other := CallbackServer{ID: 2, Title: "bot", Url: "https://other.example.com/vk", SecretKey: "x", Status: CallbackServerOk}
+	params := EnsureCallbackServerParams{
+		GroupId:   1,
+		Url:       bot.Url,
+		Title:     bot.Title,
+		SecretKey: bot.SecretKey,
+		Events:    []CallbackEventType{"message_new"},
+	}
+
+	tests := []struct {
+		name     string
+		servers  []CallbackServer
+		serverId int
+		events   map[string]int
+		calls    []string
+		id       int
+	}{
+		{
+			"add",
+			nil, 0,
+			map[string]int{"message_new": 0, "wall_post_new": 1},
+			[]string{"groups.addCallbackServer", "groups.setCallbackSettings"},
+			10,
+		},
+		{
+			"server with the same title is not taken",
+			[]CallbackServer{other}, 0,
+			map[string]int{"message_new": 1, "wall_post_new": 0},
+			[]string{"groups.addCallbackServer"},
+			11,
+		},
+		{
+			"edit changed secret",
+			[]CallbackServer{other, func() CallbackServer { s := bot; s.SecretKey = "old"; return s }()}, 0,
+			map[string]int{"message_new": 1, "wall_post_new": 0},
+			[]string{"groups.editCallbackServer"},
+			1,
+		},
+		{
+			"edit failed server",
+			[]CallbackServer{func() CallbackServer { s := bot; s.Status = CallbackServerFail; return s }()}, 0,
+			map[string]int{"message_new": 1, "wall_post_new": 0},
+			[]string{"groups.editCallbackServer"},
+			1,
+		},
+		{
+			"edit by id",
+			[]CallbackServer{bot, other}, 2,
+			map[string]int{"message_new": 1, "wall_post_new": 0},
+			[]string{"groups.editCallbackServer"},
+			2,
+		},
+		{
+			"nothing to do",
+			[]CallbackServer{other, bot}, 0,
+			map[string]int{"message_new": 1, "wall_post_new": 0},
+			nil,
+			1,
+		},
+	}
+	for _, tt := range tests {
+		api := &callbackAPI{servers: append([]CallbackServer(nil), tt.servers...), events: tt.events}
+		srv := httptest.NewServer(api)
+		vk := WithToken("token")
+		vk.ApiUrl = srv.URL + "/"
+
+		p := params
+		p.ServerId = tt.serverId
+		p.PollInterval = time.Millisecond
+		server, err := vk.Groups.EnsureCallbackServer(p)
+		srv.Close()
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+
+		if server.ID != tt.id || server.Url != p.Url || server.SecretKey != p.SecretKey {
+			t.Errorf("%s: got server %+v, want %d", tt.name, server, tt.id)
+		}
+		if !reflect.DeepEqual(api.calls, tt.calls) {
+			t.Errorf("%s: got calls %v, want %v", tt.name, api.calls, tt.calls)
+		}
+		if !reflect.DeepEqual(api.events, map[string]int{"message_new": 1, "wall_post_new": 0}) {
+			t.Errorf("%s: got events %v", tt.name, api.events)
+		}
+		for i, s := range api.servers {
+			if s.ID != tt.id && s != tt.servers[i] {
+				t.Errorf("%s: server %d is changed", tt.name, s.ID)
+			}
+		}
+	}
+}
+
+func TestEnsureCallbackServerNotFound(t *testing.T) {
+	srv := httptest.NewServer(&callbackAPI{})
+	defer srv.Close()
+	vk := WithToken("token")
+	vk.ApiUrl = srv.URL + "/"
+
+	_, err := vk.Groups.EnsureCallbackServer(EnsureCallbackServerParams{GroupId: 1, ServerId: 3, Url: "https://bot.example.com/vk"})
+	if serverErr, ok := err.(*CallbackServerError); !ok || serverErr.ServerId != 3 {
+		t.Errorf("got %v, want CallbackServerError", err)
+	}
+}
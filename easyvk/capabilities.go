@@ -0,0 +1,340 @@
+package easyvk
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// A MethodRule describes which tokens can call the method.
+type MethodRule struct {
+	// Kinds of tokens the method is available with.
+	Kinds []TokenKind
+	// Permissions the user token must have.
+	Scope Scope
+	// Permissions the community token must have.
+	GroupScope GroupScope
+}
+
+func (r MethodRule) allows(kind TokenKind) bool {
+	for _, k := range r.Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	anyToken      = []TokenKind{UserToken, GroupToken, ServiceToken}
+	userOnly      = []TokenKind{UserToken}
+	userOrGroup   = []TokenKind{UserToken, GroupToken}
+	userOrService = []TokenKind{UserToken, ServiceToken}
+	serviceOnly   = []TokenKind{ServiceToken}
+)
+
+var (
+	rulesMu     sync.RWMutex
+	methodRules = map[string]MethodRule{
+		"execute": {Kinds: anyToken},
+
+		"account.banUser":           {Kinds: userOnly},
+		"account.getAppPermissions": {Kinds: userOrGroup},
+		"account.getBanned":         {Kinds: userOnly},
+		"account.getCounters":       {Kinds: userOnly},
+		"account.getInfo":           {Kinds: userOnly},
+		"account.getProfileInfo":    {Kinds: userOnly},
+		"account.setOffline":        {Kinds: userOnly},
+		"account.setOnline":         {Kinds: userOnly},
+		"account.unbanUser":         {Kinds: userOnly},
+
+		"board.addTopic":       {Kinds: userOnly},
+		"board.closeTopic":     {Kinds: userOnly},
+		"board.createComment":  {Kinds: userOnly},
+		"board.deleteComment":  {Kinds: userOrGroup},
+		"board.deleteTopic":    {Kinds: userOnly},
+		"board.editComment":    {Kinds: userOnly},
+		"board.editTopic":      {Kinds: userOnly},
+		"board.fixTopic":       {Kinds: userOnly},
+		"board.getComments":    {Kinds: anyToken},
+		"board.getTopics":      {Kinds: anyToken},
+		"board.openTopic":      {Kinds: userOnly},
+		"board.restoreComment": {Kinds: userOrGroup},
+		"board.unfixTopic":     {Kinds: userOnly},
+
+		"fave.getLinks":  {Kinds: userOnly},
+		"fave.getPhotos": {Kinds: userOnly},
+		"fave.getUsers":  {Kinds: userOnly},
+		"fave.getVideos": {Kinds: userOnly},
+
+		"groups.addCallbackServer":           {Kinds: userOrGroup, GroupScope: GroupScopeManage},
+		"groups.approveRequest":              {Kinds: userOnly, Scope: ScopeGroups},
+		"groups.banUser":                     {Kinds: userOnly, Scope: ScopeGroups},
+		"groups.create":                      {Kinds: userOnly, Scope: ScopeGroups},
+		"groups.edit":                        {Kinds: userOrGroup, Scope: ScopeGroups, GroupScope: GroupScopeManage},
+		"groups.editCallbackServer":          {Kinds: userOrGroup, GroupScope: GroupScopeManage},
+		"groups.editManager":                 {Kinds: userOnly, Scope: ScopeGroups},
+		"groups.get":                         {Kinds: userOnly},
+		"groups.getBanned":                   {Kinds: userOrGroup, GroupScope: GroupScopeManage},
+		"groups.getById":                     {Kinds: anyToken},
+		"groups.getCallbackConfirmationCode": {Kinds: userOrGroup, GroupScope: GroupScopeManage},
+		"groups.getCallbackServers":          {Kinds: userOrGroup, GroupScope: GroupScopeManage},
+		"groups.getCallbackSettings":         {Kinds: userOrGroup, GroupScope: GroupScopeManage},
+		"groups.getInvites":                  {Kinds: userOnly},
+		"groups.getLongPollServer":           {Kinds: userOrGroup, GroupScope: GroupScopeManage},
+		"groups.getMembers":                  {Kinds: anyToken},
+		"groups.getRequests":                 {Kinds: userOnly, Scope: ScopeGroups},
+		"groups.getSettings":                 {Kinds: userOrGroup, GroupScope: GroupScopeManage},
+		"groups.getTokenPermissions":         {Kinds: []TokenKind{GroupToken}},
+		"groups.invite":                      {Kinds: userOnly, Scope: ScopeGroups},
+		"groups.isMember":                    {Kinds: anyToken},
+		"groups.join":                        {Kinds: userOnly, Scope: ScopeGroups},
+		"groups.leave":                       {Kinds: userOnly, Scope: ScopeGroups},
+		"groups.removeUser":                  {Kinds: userOnly, Scope: ScopeGroups},
+		"groups.search":                      {Kinds: userOnly},
+		"groups.setCallbackSettings":         {Kinds: userOrGroup, GroupScope: GroupScopeManage},
+		"groups.unban":                       {Kinds: userOnly, Scope: ScopeGroups},
+
+		"likes.add":     {Kinds: userOnly, Scope: ScopeWall},
+		"likes.delete":  {Kinds: userOnly, Scope: ScopeWall},
+		"likes.getList": {Kinds: anyToken},
+		"likes.isLiked": {Kinds: userOrService},
+
+		"market.add":            {Kinds: userOnly, Scope: ScopeMarket},
+		"market.addAlbum":       {Kinds: userOnly, Scope: ScopeMarket},
+		"market.addToAlbum":     {Kinds: userOnly, Scope: ScopeMarket},
+		"market.createComment":  {Kinds: userOnly, Scope: ScopeMarket},
+		"market.delete":         {Kinds: userOnly, Scope: ScopeMarket},
+		"market.deleteComment":  {Kinds: userOrGroup, Scope: ScopeMarket},
+		"market.edit":           {Kinds: userOnly, Scope: ScopeMarket},
+		"market.editAlbum":      {Kinds: userOnly, Scope: ScopeMarket},
+		"market.editOrder":      {Kinds: userOrGroup, Scope: ScopeMarket, GroupScope: GroupScopeManage},
+		"market.get":            {Kinds: anyToken},
+		"market.getAlbums":      {Kinds: anyToken},
+		"market.getById":        {Kinds: anyToken},
+		"market.getCategories":  {Kinds: anyToken},
+		"market.getComments":    {Kinds: userOrService, Scope: ScopeMarket},
+		"market.getGroupOrders": {Kinds: userOrGroup, Scope: ScopeMarket, GroupScope: GroupScopeManage},
+		"market.getOrderById":   {Kinds: userOnly, Scope: ScopeMarket},
+		"market.getOrders":      {Kinds: userOnly, Scope: ScopeMarket},
+		"market.reorderItems":   {Kinds: userOnly, Scope: ScopeMarket},
+		"market.restore":        {Kinds: userOnly, Scope: ScopeMarket},
+		"market.search":         {Kinds: anyToken},
+
+		"photos.confirmTag":            {Kinds: userOnly, Scope: ScopePhotos},
+		"photos.copy":                  {Kinds: userOnly, Scope: ScopePhotos},
+		"photos.createAlbum":           {Kinds: userOnly, Scope: ScopePhotos},
+		"photos.createComment":         {Kinds: userOnly, Scope: ScopePhotos},
+		"photos.delete":                {Kinds: userOnly, Scope: ScopePhotos},
+		"photos.deleteAlbum":           {Kinds: userOnly, Scope: ScopePhotos},
+		"photos.deleteComment":         {Kinds: userOrGroup, Scope: ScopePhotos},
+		"photos.edit":                  {Kinds: userOnly, Scope: ScopePhotos},
+		"photos.editAlbum":             {Kinds: userOnly, Scope: ScopePhotos},
+		"photos.editComment":           {Kinds: userOnly, Scope: ScopePhotos},
+		"photos.get":                   {Kinds: anyToken},
+		"photos.getAlbums":             {Kinds: anyToken},
+		"photos.getAll":                {Kinds: userOrService},
+		"photos.getAllComments":        {Kinds: userOnly, Scope: ScopePhotos},
+		"photos.getById":               {Kinds: anyToken},
+		"photos.getComments":           {Kinds: anyToken},
+		"photos.getMarketUploadServer": {Kinds: userOnly, Scope: ScopePhotos},
+		"photos.getNewTags":            {Kinds: userOnly, Scope: ScopePhotos},
+		"photos.getTags":               {Kinds: userOnly, Scope: ScopePhotos},
+		"photos.getUploadServer":       {Kinds: userOnly, Scope: ScopePhotos},
+		"photos.getWallUploadServer":   {Kinds: userOnly, Scope: ScopePhotos},
+		"photos.makeCover":             {Kinds: userOnly, Scope: ScopePhotos},
+		"photos.move":                  {Kinds: userOnly, Scope: ScopePhotos},
+		"photos.putTag":                {Kinds: userOnly, Scope: ScopePhotos},
+		"photos.removeTag":             {Kinds: userOnly, Scope: ScopePhotos},
+		"photos.reorderAlbums":         {Kinds: userOnly, Scope: ScopePhotos},
+		"photos.restore":               {Kinds: userOnly, Scope: ScopePhotos},
+		"photos.restoreComment":        {Kinds: userOrGroup, Scope: ScopePhotos},
+		"photos.save":                  {Kinds: userOnly, Scope: ScopePhotos},
+		"photos.saveMarketPhoto":       {Kinds: userOnly, Scope: ScopePhotos},
+		"photos.saveWallPhoto":         {Kinds: userOnly, Scope: ScopePhotos},
+
+		"secure.addAppEvent":      {Kinds: serviceOnly},
+		"secure.checkToken":       {Kinds: serviceOnly},
+		"secure.getSMSHistory":    {Kinds: serviceOnly},
+		"secure.sendNotification": {Kinds: serviceOnly},
+
+		"status.get": {Kinds: userOrGroup},
+		"status.set": {Kinds: userOnly, Scope: ScopeStatus},
+
+		"users.get": {Kinds: anyToken},
+
+		"video.add":             {Kinds: userOnly, Scope: ScopeVideo},
+		"video.addAlbum":        {Kinds: userOnly, Scope: ScopeVideo},
+		"video.addToAlbum":      {Kinds: userOnly, Scope: ScopeVideo},
+		"video.createComment":   {Kinds: userOnly, Scope: ScopeVideo},
+		"video.delete":          {Kinds: userOnly, Scope: ScopeVideo},
+		"video.deleteAlbum":     {Kinds: userOnly, Scope: ScopeVideo},
+		"video.deleteComment":   {Kinds: userOrGroup, Scope: ScopeVideo},
+		"video.edit":            {Kinds: userOnly, Scope: ScopeVideo},
+		"video.editAlbum":       {Kinds: userOnly, Scope: ScopeVideo},
+		"video.editComment":     {Kinds: userOnly, Scope: ScopeVideo},
+		"video.get":             {Kinds: anyToken, Scope: ScopeVideo},
+		"video.getAlbums":       {Kinds: anyToken, Scope: ScopeVideo},
+		"video.getComments":     {Kinds: anyToken, Scope: ScopeVideo},
+		"video.removeFromAlbum": {Kinds: userOnly, Scope: ScopeVideo},
+		"video.restore":         {Kinds: userOnly, Scope: ScopeVideo},
+		"video.search":          {Kinds: userOnly, Scope: ScopeVideo},
+
+		"wall.createComment": {Kinds: userOrGroup, Scope: ScopeWall},
+		"wall.deleteComment": {Kinds: userOrGroup, Scope: ScopeWall},
+		"wall.get":           {Kinds: anyToken},
+		"wall.getById":       {Kinds: anyToken},
+		"wall.getComments":   {Kinds: anyToken},
+		"wall.post":          {Kinds: userOnly, Scope: ScopeWall},
+	}
+)
+
+// RegisterMethod adds or replaces the rule of the method,
+// e.g. for methods called with VK.Request.
+func RegisterMethod(method string, rule MethodRule) {
+	rulesMu.Lock()
+	methodRules[method] = rule
+	rulesMu.Unlock()
+}
+
+// MethodRuleFor returns the rule of the method
+// and false if the method is not registered.
+func MethodRuleFor(method string) (MethodRule, bool) {
+	rulesMu.RLock()
+	rule, ok := methodRules[method]
+	rulesMu.RUnlock()
+	return rule, ok
+}
+
+// Methods returns names of all registered methods, sorted.
+func Methods() []string {
+	rulesMu.RLock()
+	defer rulesMu.RUnlock()
+	methods := make([]string, 0, len(methodRules))
+	for method := range methodRules {
+		methods = append(methods, method)
+	}
+	sort.Strings(methods)
+	return methods
+}
+
+// A CapabilityError describes why the token can't call the method.
+type CapabilityError struct {
+	Method string
+	Kind   TokenKind
+	// Kinds the method is available with.
+	Kinds []TokenKind
+	// Missing permissions, only one of them is set.
+	MissingScope      Scope
+	MissingGroupScope GroupScope
+}
+
+func (e *CapabilityError) Error() string {
+	if e.MissingScope != 0 {
+		return fmt.Sprintf("%s requires scope %s", e.Method, e.MissingScope)
+	}
+	if e.MissingGroupScope != 0 {
+		return fmt.Sprintf("%s requires community scope %s", e.Method, e.MissingGroupScope)
+	}
+	kinds := make([]string, len(e.Kinds))
+	for i, k := range e.Kinds {
+		kinds[i] = k.String()
+	}
+	return fmt.Sprintf("%s is not available with %s token, only with: %s",
+		e.Method, e.Kind, strings.Join(kinds, ", "))
+}
+
+// CanCall returns *CapabilityError if the token can't call the method.
+// Methods without a rule and tokens of unknown kind are always allowed.
+// Scopes are checked only if they are known.
+func (vk *VK) CanCall(method string) error {
+	if vk.TokenKind == UnknownToken {
+		return nil
+	}
+	rule, ok := MethodRuleFor(method)
+	if !ok {
+		return nil
+	}
+	if !rule.allows(vk.TokenKind) {
+		return &CapabilityError{Method: method, Kind: vk.TokenKind, Kinds: rule.Kinds}
+	}
+
+	switch vk.TokenKind {
+	case UserToken:
+		if vk.Scope != 0 && !vk.Scope.Has(rule.Scope) {
+			return &CapabilityError{
+				Method:       method,
+				Kind:         vk.TokenKind,
+				Kinds:        rule.Kinds,
+				MissingScope: rule.Scope &^ vk.Scope,
+			}
+		}
+	case GroupToken:
+		if vk.GroupScope != 0 && !vk.GroupScope.Has(rule.GroupScope) {
+			return &CapabilityError{
+				Method:            method,
+				Kind:              vk.TokenKind,
+				Kinds:             rule.Kinds,
+				MissingGroupScope: rule.GroupScope &^ vk.GroupScope,
+			}
+		}
+	}
+	return nil
+}
+
+// AvailableMethods returns registered methods
+// the token can call, sorted.
+func (vk *VK) AvailableMethods() []string {
+	var methods []string
+	for _, method := range Methods() {
+		if vk.CanCall(method) == nil {
+			methods = append(methods, method)
+		}
+	}
+	return methods
+}
+
+// notGroupTokenCodes are returned by groups.getTokenPermissions
+// called with a user token (access denied)
+// or a service token (application authorization failed).
+var notGroupTokenCodes = map[int]bool{
+	15: true,
+	28: true,
+}
+
+// DetectToken asks VK what kind of token AccessToken is
+// and sets TokenKind with Scope or GroupScope.
+func (vk *VK) DetectToken() error {
+	vk.TokenKind = UnknownToken
+
+	// only community tokens can get their permissions
+	groupScope, err := vk.Groups.GetTokenPermissions()
+	if err == nil {
+		vk.TokenKind = GroupToken
+		vk.GroupScope = groupScope
+		return nil
+	}
+	// other errors like network failures say nothing about the kind
+	if vkErr, ok := err.(*Error); !ok || !notGroupTokenCodes[vkErr.Code] {
+		return err
+	}
+
+	users, err := vk.Users.Get(nil, nil, "")
+	if err != nil {
+		return err
+	}
+	// service token has no current user
+	if len(users) == 0 {
+		vk.TokenKind = ServiceToken
+		return nil
+	}
+
+	scope, err := vk.Account.GetAppPermissions(uint(users[0].ID))
+	if err != nil {
+		return err
+	}
+	vk.TokenKind = UserToken
+	vk.Scope = scope
+	return nil
+}
@@ -0,0 +1,626 @@
+package easyvk
+
+import (
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// marketItemsPerGet is the max count of market.get.
+const marketItemsPerGet = 200
+
+// A CatalogItem describes a product in the local feed.
+type CatalogItem struct {
+	SKU         string  `json:"sku"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	CategoryID  int     `json:"category_id"`
+	Price       float64 `json:"price"`
+	OldPrice    float64 `json:"old_price"`
+	URL         string  `json:"url"`
+	// Paths of photo files, the first one is the main photo.
+	Photos []string `json:"photos"`
+}
+
+// ReadCatalogFeed reads products from a JSON or CSV file.
+//
+// JSON file is an array of CatalogItem. CSV file has a header
+// with columns named like json tags of CatalogItem,
+// photos are separated by "|". Relative paths of photos
+// are resolved against the directory of the feed.
+func ReadCatalogFeed(path string) ([]CatalogItem, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var items []CatalogItem
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		items, err = readCatalogCSV(f)
+	} else {
+		err = json.NewDecoder(f).Decode(&items)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = validateCatalogItems(items)
+	if err != nil {
+		return nil, err
+	}
+
+	dir := filepath.Dir(path)
+	for i := range items {
+		item := &items[i]
+		for j, photo := range item.Photos {
+			if !filepath.IsAbs(photo) {
+				item.Photos[j] = filepath.Join(dir, photo)
+			}
+		}
+	}
+	return items, nil
+}
+
+// validateCatalogItems checks that every item has
+// a unique SKU and at least one photo.
+func validateCatalogItems(items []CatalogItem) error {
+	seen := map[string]bool{}
+	for i, item := range items {
+		if item.SKU == "" {
+			return fmt.Errorf("item %d has no sku", i+1)
+		}
+		if seen[item.SKU] {
+			return fmt.Errorf("duplicate sku %q", item.SKU)
+		}
+		seen[item.SKU] = true
+		if len(item.Photos) == 0 {
+			return fmt.Errorf("item %q has no photos", item.SKU)
+		}
+	}
+	return nil
+}
+
+func readCatalogCSV(r io.Reader) ([]CatalogItem, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	columns := map[string]int{}
+	for i, name := range rows[0] {
+		columns[strings.TrimSpace(name)] = i
+	}
+	if _, ok := columns["sku"]; !ok {
+		return nil, errors.New("csv feed has no sku column")
+	}
+
+	var items []CatalogItem
+	for n, row := range rows[1:] {
+		get := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(row) {
+				return ""
+			}
+			return strings.TrimSpace(row[i])
+		}
+		number := func(name string) (float64, error) {
+			v := get(name)
+			if v == "" {
+				return 0, nil
+			}
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return 0, fmt.Errorf("line %d: bad %s %q", n+2, name, v)
+			}
+			return f, nil
+		}
+
+		item := CatalogItem{
+			SKU:         get("sku"),
+			Name:        get("name"),
+			Description: get("description"),
+			URL:         get("url"),
+		}
+		category, err := number("category_id")
+		if err != nil {
+			return nil, err
+		}
+		item.CategoryID = int(category)
+		item.Price, err = number("price")
+		if err != nil {
+			return nil, err
+		}
+		item.OldPrice, err = number("old_price")
+		if err != nil {
+			return nil, err
+		}
+		for _, photo := range strings.Split(get("photos"), "|") {
+			if photo = strings.TrimSpace(photo); photo != "" {
+				item.Photos = append(item.Photos, photo)
+			}
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
+
+// A CatalogState describes what has been synced,
+// it's kept between runs to skip photos which haven't changed.
+type CatalogState struct {
+	// by SKU
+	Items map[string]CatalogStateItem `json:"items"`
+}
+
+// A CatalogStateItem describes a synced product.
+type CatalogStateItem struct {
+	ItemID int                 `json:"item_id"`
+	Photos []CatalogStatePhoto `json:"photos"`
+}
+
+// A CatalogStatePhoto describes an uploaded photo of the product.
+type CatalogStatePhoto struct {
+	Checksum string `json:"checksum"`
+	PhotoID  int    `json:"photo_id"`
+}
+
+// LoadCatalogState reads the state from path,
+// the state is empty if there is no file.
+func LoadCatalogState(path string) (*CatalogState, error) {
+	state := &CatalogState{Items: map[string]CatalogStateItem{}}
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, state)
+	if err != nil {
+		return nil, err
+	}
+	if state.Items == nil {
+		state.Items = map[string]CatalogStateItem{}
+	}
+	return state, nil
+}
+
+// Save writes the state to path.
+func (s *CatalogState) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	err = ioutil.WriteFile(tmp, data, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// CatalogActionType is a type of the change of the shop.
+type CatalogActionType string
+
+const (
+	CatalogCreate CatalogActionType = "create"
+	CatalogUpdate CatalogActionType = "update"
+	CatalogDelete CatalogActionType = "delete"
+)
+
+// A CatalogAction describes a change needed
+// to bring the product in line with the feed.
+type CatalogAction struct {
+	Type CatalogActionType
+	SKU  string
+	// ID of the product in VK, zero for CatalogCreate.
+	ItemID int
+	// Item from the feed, nil for CatalogDelete.
+	Item *CatalogItem
+	// Changed fields for CatalogUpdate: name, description,
+	// category, price, old_price, url, sku and photos.
+	Changes []string
+
+	photos []catalogPhoto
+}
+
+type catalogPhoto struct {
+	path     string
+	checksum string
+	// zero if the photo must be uploaded
+	photoID int
+}
+
+// Uploads returns paths of photos the action uploads.
+func (a CatalogAction) Uploads() []string {
+	var paths []string
+	for _, photo := range a.photos {
+		if photo.photoID == 0 {
+			paths = append(paths, photo.path)
+		}
+	}
+	return paths
+}
+
+func (a CatalogAction) String() string {
+	switch a.Type {
+	case CatalogCreate:
+		return fmt.Sprintf("create %s, %d photos", a.SKU, len(a.photos))
+	case CatalogUpdate:
+		s := fmt.Sprintf("update %s (item %d): %s", a.SKU, a.ItemID, strings.Join(a.Changes, ", "))
+		if uploads := len(a.Uploads()); uploads > 0 {
+			s += fmt.Sprintf(", %d new photos", uploads)
+		}
+		return s
+	}
+	return fmt.Sprintf("delete %s (item %d)", a.SKU, a.ItemID)
+}
+
+// A CatalogPlan describes changes needed to converge the shop to the feed.
+type CatalogPlan struct {
+	Actions   []CatalogAction
+	Unchanged int
+}
+
+// A CatalogReport describes a result of CatalogSync.Apply.
+type CatalogReport struct {
+	Created        int
+	Updated        int
+	Deleted        int
+	Unchanged      int
+	PhotosUploaded int
+	Failed         []CatalogFailure
+}
+
+// A CatalogFailure describes an action which has failed.
+type CatalogFailure struct {
+	Action CatalogAction
+	Err    error
+}
+
+// A CatalogSync describes a way to mirror
+// a local product feed to the community shop.
+// Products are matched by SKU.
+type CatalogSync struct {
+	vk        *VK
+	groupID   int
+	statePath string
+
+	// Delete products with SKU which is not in the feed.
+	DeleteMissing bool
+	// Limit of API requests per second, 3 by default.
+	RequestsPerSecond int
+}
+
+// NewCatalogSync returns a sync of the shop of the community
+// which keeps its state in the file at statePath.
+func NewCatalogSync(vk *VK, groupID int, statePath string) *CatalogSync {
+	return &CatalogSync{vk: vk, groupID: groupID, statePath: statePath}
+}
+
+func (c *CatalogSync) limiter() *rateLimiter {
+	perSecond := c.RequestsPerSecond
+	if perSecond <= 0 {
+		perSecond = 3
+	}
+	return newRateLimiter(perSecond)
+}
+
+// Plan compares the feed with products of the shop
+// and returns changes needed, nothing is changed.
+// Every item must have a unique SKU and at least one photo.
+func (c *CatalogSync) Plan(items []CatalogItem) (*CatalogPlan, error) {
+	err := validateCatalogItems(items)
+	if err != nil {
+		return nil, err
+	}
+	state, err := LoadCatalogState(c.statePath)
+	if err != nil {
+		return nil, err
+	}
+	current, err := c.currentItems(c.limiter())
+	if err != nil {
+		return nil, err
+	}
+	return c.plan(items, current, state)
+}
+
+// plan compares the feed with the current products of the shop.
+func (c *CatalogSync) plan(items []CatalogItem, current []MarketItemObject, state *CatalogState) (*CatalogPlan, error) {
+	byID := map[int]*MarketItemObject{}
+	bySKU := map[string]*MarketItemObject{}
+	for i := range current {
+		item := &current[i]
+		byID[item.ID] = item
+		if item.SKU != "" {
+			bySKU[item.SKU] = item
+		}
+	}
+
+	plan := &CatalogPlan{}
+	inFeed := map[string]bool{}
+	matched := map[int]bool{}
+	for i := range items {
+		item := &items[i]
+		inFeed[item.SKU] = true
+
+		existing := bySKU[item.SKU]
+		if existing == nil {
+			// VK may not return sku, look for the synced item
+			existing = byID[state.Items[item.SKU].ItemID]
+		}
+		photos, photosChanged, err := planPhotos(item, state.Items[item.SKU])
+		if err != nil {
+			return nil, err
+		}
+
+		if existing == nil {
+			plan.Actions = append(plan.Actions, CatalogAction{
+				Type:   CatalogCreate,
+				SKU:    item.SKU,
+				Item:   item,
+				photos: photos,
+			})
+			continue
+		}
+		matched[existing.ID] = true
+
+		changes := catalogChanges(item, existing)
+		if photosChanged || state.Items[item.SKU].ItemID != existing.ID {
+			changes = append(changes, "photos")
+		}
+		if len(changes) == 0 {
+			plan.Unchanged++
+			continue
+		}
+		plan.Actions = append(plan.Actions, CatalogAction{
+			Type:    CatalogUpdate,
+			SKU:     item.SKU,
+			ItemID:  existing.ID,
+			Item:    item,
+			Changes: changes,
+			photos:  photos,
+		})
+	}
+
+	if c.DeleteMissing {
+		for _, item := range current {
+			if matched[item.ID] {
+				continue
+			}
+			sku := item.SKU
+			if sku == "" {
+				sku = stateSKU(state, item.ID)
+			}
+			// products added by hand have no SKU, leave them
+			if sku == "" || inFeed[sku] {
+				continue
+			}
+			plan.Actions = append(plan.Actions, CatalogAction{
+				Type:   CatalogDelete,
+				SKU:    sku,
+				ItemID: item.ID,
+			})
+		}
+	}
+	return plan, nil
+}
+
+// Apply runs actions of the plan. Failed actions are
+// collected in the report and don't stop the others.
+// The state is saved after every successful action.
+func (c *CatalogSync) Apply(plan *CatalogPlan) (*CatalogReport, error) {
+	state, err := LoadCatalogState(c.statePath)
+	if err != nil {
+		return nil, err
+	}
+	limiter := c.limiter()
+
+	report := &CatalogReport{Unchanged: plan.Unchanged}
+	for _, action := range plan.Actions {
+		switch action.Type {
+		case CatalogCreate, CatalogUpdate:
+			var uploaded int
+			uploaded, err = c.applyItem(action, state, limiter)
+			report.PhotosUploaded += uploaded
+		case CatalogDelete:
+			limiter.Wait()
+			_, err = c.vk.Market.Delete(-c.groupID, action.ItemID)
+			if err == nil {
+				delete(state.Items, action.SKU)
+			}
+		}
+		if err != nil {
+			report.Failed = append(report.Failed, CatalogFailure{Action: action, Err: err})
+			continue
+		}
+
+		switch action.Type {
+		case CatalogCreate:
+			report.Created++
+		case CatalogUpdate:
+			report.Updated++
+		case CatalogDelete:
+			report.Deleted++
+		}
+		err = state.Save(c.statePath)
+		if err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// applyItem creates or updates the product and
+// returns the number of uploaded photos.
+func (c *CatalogSync) applyItem(action CatalogAction, state *CatalogState, limiter *rateLimiter) (int, error) {
+	uploaded := 0
+	synced := CatalogStateItem{ItemID: action.ItemID}
+	for i, photo := range action.photos {
+		if photo.photoID == 0 {
+			saved, err := c.vk.Photos.uploadMarketPhoto(c.groupID, i == 0, photo.path, limiter.Wait)
+			if err != nil {
+				return uploaded, err
+			}
+			photo.photoID = saved.ID
+			uploaded++
+		}
+		synced.Photos = append(synced.Photos, CatalogStatePhoto{
+			Checksum: photo.checksum,
+			PhotoID:  photo.photoID,
+		})
+	}
+
+	item := action.Item
+	params := MarketAddParams{
+		OwnerID:     -c.groupID,
+		Name:        item.Name,
+		Description: item.Description,
+		CategoryID:  item.CategoryID,
+		Price:       item.Price,
+		OldPrice:    item.OldPrice,
+		MainPhotoID: synced.Photos[0].PhotoID,
+		URL:         item.URL,
+		SKU:         item.SKU,
+	}
+	for _, photo := range synced.Photos[1:] {
+		params.PhotoIDs = append(params.PhotoIDs, photo.PhotoID)
+	}
+
+	limiter.Wait()
+	if action.Type == CatalogCreate {
+		id, err := c.vk.Market.Add(params)
+		if err != nil {
+			return uploaded, err
+		}
+		synced.ItemID = id
+	} else {
+		_, err := c.vk.Market.Edit(MarketEditParams{ItemID: action.ItemID, MarketAddParams: params})
+		if err != nil {
+			return uploaded, err
+		}
+	}
+	state.Items[action.SKU] = synced
+	return uploaded, nil
+}
+
+// currentItems returns all products of the shop.
+func (c *CatalogSync) currentItems(limiter *rateLimiter) ([]MarketItemObject, error) {
+	var items []MarketItemObject
+	for offset := 0; ; offset += marketItemsPerGet {
+		limiter.Wait()
+		resp, err := c.vk.Market.Get(MarketGetParams{
+			OwnerID: -c.groupID,
+			Offset:  offset,
+			Count:   marketItemsPerGet,
+		})
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, resp.Items...)
+		if len(resp.Items) == 0 || offset+marketItemsPerGet >= resp.Count {
+			return items, nil
+		}
+	}
+}
+
+// planPhotos returns photos of the item with IDs
+// of those which have been uploaded already.
+func planPhotos(item *CatalogItem, synced CatalogStateItem) ([]catalogPhoto, bool, error) {
+	photos := make([]catalogPhoto, len(item.Photos))
+	changed := len(item.Photos) != len(synced.Photos)
+	for i, path := range item.Photos {
+		checksum, err := fileChecksum(path)
+		if err != nil {
+			return nil, false, err
+		}
+		photos[i] = catalogPhoto{path: path, checksum: checksum}
+		if i < len(synced.Photos) && synced.Photos[i].Checksum != checksum {
+			changed = true
+		}
+
+		// the main photo is uploaded differently,
+		// so it can be reused only as the main photo
+		for j, old := range synced.Photos {
+			if old.Checksum == checksum && (i == 0) == (j == 0) {
+				photos[i].photoID = old.PhotoID
+				break
+			}
+		}
+	}
+	return photos, changed, nil
+}
+
+// catalogChanges returns names of fields which differ.
+func catalogChanges(item *CatalogItem, existing *MarketItemObject) []string {
+	var changes []string
+	if item.Name != existing.Title {
+		changes = append(changes, "name")
+	}
+	if item.Description != existing.Description {
+		changes = append(changes, "description")
+	}
+	if item.CategoryID != existing.Category.ID {
+		changes = append(changes, "category")
+	}
+	if priceAmount(item.Price) != parseAmount(existing.Price.Amount) {
+		changes = append(changes, "price")
+	}
+	if priceAmount(item.OldPrice) != parseAmount(existing.Price.OldAmount) {
+		changes = append(changes, "old_price")
+	}
+	if item.URL != existing.URL {
+		changes = append(changes, "url")
+	}
+	if existing.SKU != "" && item.SKU != existing.SKU {
+		changes = append(changes, "sku")
+	}
+	return changes
+}
+
+// priceAmount converts the price to hundredths of the currency.
+func priceAmount(price float64) int {
+	return int(math.Round(price * 100))
+}
+
+// parseAmount parses the amount VK sends, empty is zero.
+func parseAmount(amount string) int {
+	n, _ := strconv.Atoi(amount)
+	return n
+}
+
+func stateSKU(state *CatalogState, itemID int) string {
+	for sku, item := range state.Items {
+		if item.ItemID == itemID {
+			return sku
+		}
+	}
+	return ""
+}
+
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	_, err = io.Copy(h, f)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
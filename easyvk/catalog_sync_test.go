@@ -0,0 +1,498 @@
+package easyvk
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	err := ioutil.WriteFile(path, []byte(content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReadCatalogCSV(t *testing.T) {
+	items, err := readCatalogCSV(strings.NewReader(`name, sku ,price,photos,extra
+Mug,A-1,10.5,a.jpg|  | b.jpg,x
+Cup,B-2,,,
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []CatalogItem{
+		{SKU: "A-1", Name: "Mug", Price: 10.5, Photos: []string{"a.jpg", "b.jpg"}},
+		{SKU: "B-2", Name: "Cup"},
+	}
+	if !reflect.DeepEqual(items, want) {
+		t.Errorf("got %+v, want %+v", items, want)
+	}
+
+	items, err = readCatalogCSV(strings.NewReader(""))
+	if err != nil || items != nil {
+		t.Errorf("got %+v, %v for empty feed", items, err)
+	}
+}
+
+func TestReadCatalogFeed(t *testing.T) {
+	dir := t.TempDir()
+	want := []CatalogItem{
+		{
+			SKU:         "A-1",
+			Name:        "Mug",
+			Description: "Big, white",
+			CategoryID:  1,
+			Price:       1000000,
+			OldPrice:    1200000.5,
+			URL:         "https://example.com/a",
+			Photos:      []string{filepath.Join(dir, "a.jpg"), "/abs/b.jpg"},
+		},
+		{
+			SKU:    "B-2",
+			Name:   "Cup",
+			Price:  9.99,
+			Photos: []string{filepath.Join(dir, "photos", "c.jpg")},
+		},
+	}
+
+	writeFile(t, filepath.Join(dir, "feed.csv"), `sku,name,description,category_id,price,old_price,url,photos
+A-1,Mug,"Big, white",1,1000000,1200000.5,https://example.com/a,a.jpg | /abs/b.jpg
+ B-2 , Cup ,,,9.99,,,photos/c.jpg
+`)
+	writeFile(t, filepath.Join(dir, "feed.json"), `[
+	{"sku": "A-1", "name": "Mug", "description": "Big, white", "category_id": 1,
+	 "price": 1000000, "old_price": 1200000.5, "url": "https://example.com/a",
+	 "photos": ["a.jpg", "/abs/b.jpg"]},
+	{"sku": "B-2", "name": "Cup", "price": 9.99, "photos": ["photos/c.jpg"]}
+]`)
+
+	for _, name := range []string{"feed.csv", "feed.json"} {
+		items, err := ReadCatalogFeed(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !reflect.DeepEqual(items, want) {
+			t.Errorf("%s:\ngot  %+v\nwant %+v", name, items, want)
+		}
+	}
+}
+
+func TestReadCatalogFeedErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		feed string
+		err  string
+	}{
+		{"no sku column", "name,photos\nMug,a.jpg\n", "no sku column"},
+		{"empty sku", "sku,photos\n,a.jpg\n", "item 1 has no sku"},
+		{"duplicate sku", "sku,photos\nA,a.jpg\nA,b.jpg\n", `duplicate sku "A"`},
+		{"no photos", "sku,photos\nA,\n", `item "A" has no photos`},
+		{"bad price", "sku,price,photos\nA,1e,a.jpg\n", `line 2: bad price "1e"`},
+	}
+	dir := t.TempDir()
+	for _, tt := range tests {
+		path := filepath.Join(dir, "feed.csv")
+		writeFile(t, path, tt.feed)
+		_, err := ReadCatalogFeed(path)
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("%s: got error %v, want %q", tt.name, err, tt.err)
+		}
+	}
+}
+
+func TestCatalogPlanValidates(t *testing.T) {
+	tests := []struct {
+		items []CatalogItem
+		err   string
+	}{
+		{[]CatalogItem{{SKU: "A"}}, `item "A" has no photos`},
+		{[]CatalogItem{{Photos: []string{"a.jpg"}}}, "item 1 has no sku"},
+		{[]CatalogItem{{SKU: "A", Photos: []string{"a.jpg"}}, {SKU: "A", Photos: []string{"b.jpg"}}}, `duplicate sku "A"`},
+	}
+	for _, tt := range tests {
+		// the feed is checked before any request
+		_, err := NewCatalogSync(nil, 1, "").Plan(tt.items)
+		if err == nil || err.Error() != tt.err {
+			t.Errorf("got error %v, want %q", err, tt.err)
+		}
+	}
+}
+
+func TestCatalogPlan(t *testing.T) {
+	dir := t.TempDir()
+	photo := func(name string) string {
+		path := filepath.Join(dir, name)
+		writeFile(t, path, "image "+name)
+		return path
+	}
+	checksum := func(path string) string {
+		sum, err := fileChecksum(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return sum
+	}
+	a, b, c := photo("a.jpg"), photo("b.jpg"), photo("c.jpg")
+
+	// A-1 was synced as item 10 with main photo a and extra photo b
+	state := func() *CatalogState {
+		return &CatalogState{Items: map[string]CatalogStateItem{
+			"A-1": {ItemID: 10, Photos: []CatalogStatePhoto{
+				{Checksum: checksum(a), PhotoID: 100},
+				{Checksum: checksum(b), PhotoID: 101},
+			}},
+			"OLD": {ItemID: 12},
+		}}
+	}
+	synced := CatalogItem{SKU: "A-1", Name: "Mug", Price: 10, Photos: []string{a, b}}
+	// VK doesn't send sku of the item, it's matched by the state
+	item10 := MarketItemObject{ID: 10, Title: "Mug", Price: MarketPrice{Amount: "1000"}}
+	item11 := MarketItemObject{ID: 11, Title: "Cup", SKU: "B-2", Price: MarketPrice{Amount: "500"}}
+	// added by hand
+	item13 := MarketItemObject{ID: 13, Title: "Handmade"}
+	item12 := MarketItemObject{ID: 12, Title: "Removed"}
+
+	tests := []struct {
+		name          string
+		items         []CatalogItem
+		current       []MarketItemObject
+		deleteMissing bool
+		want          []string
+		uploads       [][]string
+		unchanged     int
+	}{
+		{
+			name:      "unchanged",
+			items:     []CatalogItem{synced},
+			current:   []MarketItemObject{item10},
+			unchanged: 1,
+		},
+		{
+			name:    "price in cents",
+			items:   []CatalogItem{{SKU: "A-1", Name: "Mug", Price: 10.01, Photos: []string{a, b}}},
+			current: []MarketItemObject{item10},
+			want:    []string{"update A-1 (item 10): price"},
+			uploads: [][]string{nil},
+		},
+		{
+			name:    "create",
+			items:   []CatalogItem{{SKU: "C-3", Name: "Plate", Photos: []string{c, a}}},
+			current: []MarketItemObject{item10},
+			want:    []string{"create C-3, 2 photos"},
+			uploads: [][]string{{c, a}},
+		},
+		{
+			name:    "matched by sku without state",
+			items:   []CatalogItem{{SKU: "B-2", Name: "Cup", Price: 5, Photos: []string{c}}},
+			current: []MarketItemObject{item11},
+			want:    []string{"update B-2 (item 11): photos, 1 new photos"},
+			uploads: [][]string{{c}},
+		},
+		{
+			name:    "new extra photo",
+			items:   []CatalogItem{{SKU: "A-1", Name: "Mug", Price: 10, Photos: []string{a, b, c}}},
+			current: []MarketItemObject{item10},
+			want:    []string{"update A-1 (item 10): photos, 1 new photos"},
+			uploads: [][]string{{c}},
+		},
+		{
+			name:    "main photo is not reused as extra",
+			items:   []CatalogItem{{SKU: "A-1", Name: "Mug", Price: 10, Photos: []string{b, a}}},
+			current: []MarketItemObject{item10},
+			want:    []string{"update A-1 (item 10): photos, 2 new photos"},
+			uploads: [][]string{{b, a}},
+		},
+		{
+			name:          "delete missing",
+			items:         []CatalogItem{synced},
+			current:       []MarketItemObject{item10, item11, item12, item13},
+			deleteMissing: true,
+			want:          []string{"delete B-2 (item 11)", "delete OLD (item 12)"},
+			uploads:       [][]string{nil, nil},
+			unchanged:     1,
+		},
+		{
+			name:      "keep missing",
+			items:     []CatalogItem{synced},
+			current:   []MarketItemObject{item10, item11, item12, item13},
+			unchanged: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		sync := NewCatalogSync(nil, 1, "")
+		sync.DeleteMissing = tt.deleteMissing
+		plan, err := sync.plan(tt.items, tt.current, state())
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+
+		var got []string
+		var uploads [][]string
+		for _, action := range plan.Actions {
+			got = append(got, action.String())
+			uploads = append(uploads, action.Uploads())
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got actions %q, want %q", tt.name, got, tt.want)
+		}
+		if !reflect.DeepEqual(uploads, tt.uploads) {
+			t.Errorf("%s: got uploads %q, want %q", tt.name, uploads, tt.uploads)
+		}
+		if plan.Unchanged != tt.unchanged {
+			t.Errorf("%s: got %d unchanged, want %d", tt.name, plan.Unchanged, tt.unchanged)
+		}
+	}
+}
+
+func TestPriceAmount(t *testing.T) {
+	tests := []struct {
+		price  float64
+		amount int
+	}{
+		{0, 0},
+		{10, 1000},
+		{19.99, 1999},
+		{0.1 + 0.2, 30},
+		{1000000, 100000000},
+	}
+	for _, tt := range tests {
+		if got := priceAmount(tt.price); got != tt.amount {
+			t.Errorf("%v: got %d, want %d", tt.price, got, tt.amount)
+		}
+	}
+
+	for amount, want := range map[string]int{"": 0, "1999": 1999, "0": 0, "bad": 0} {
+		if got := parseAmount(amount); got != want {
+			t.Errorf("%q: got %d, want %d", amount, got, want)
+		}
+	}
+}
+
+func TestCatalogChangesPrice(t *testing.T) {
+	tests := []struct {
+		price, oldPrice float64
+		amount, old     string
+		want            []string
+	}{
+		{1000000, 0, "100000000", "", nil},
+		{0.1 + 0.2, 0, "30", "", nil},
+		{19.99, 25, "1999", "2500", nil},
+		{19.99, 25, "1998", "2500", []string{"price"}},
+		{19.99, 0, "1999", "2500", []string{"old_price"}},
+	}
+	for _, tt := range tests {
+		item := &CatalogItem{Price: tt.price, OldPrice: tt.oldPrice}
+		existing := &MarketItemObject{Price: MarketPrice{Amount: tt.amount, OldAmount: tt.old}}
+		got := catalogChanges(item, existing)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%v/%v vs %s/%s: got %q, want %q", tt.price, tt.oldPrice, tt.amount, tt.old, got, tt.want)
+		}
+	}
+}
+
+// shopServer is a fake shop of the community 1. It keeps products,
+// accepts photo uploads and fails to delete products in failDelete.
+type shopServer struct {
+	*httptest.Server
+	items      map[int]*MarketItemObject
+	nextID     int
+	failDelete map[int]bool
+	// main_photo flags of requested upload servers
+	uploads []string
+	edits   []url.Values
+}
+
+func newShopServer(t *testing.T, items ...MarketItemObject) *shopServer {
+	s := &shopServer{items: map[int]*MarketItemObject{}, nextID: 20, failDelete: map[int]bool{}}
+	for i := range items {
+		s.items[items[i].ID] = &items[i]
+	}
+	respond := func(w http.ResponseWriter, v interface{}) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"response": v})
+	}
+	amount := func(price string) string {
+		f, _ := strconv.ParseFloat(price, 64)
+		return strconv.Itoa(priceAmount(f))
+	}
+	update := func(item *MarketItemObject, r *http.Request) {
+		item.Title = r.FormValue("name")
+		item.Description = r.FormValue("description")
+		item.Category.ID, _ = strconv.Atoi(r.FormValue("category_id"))
+		item.Price.Amount = amount(r.FormValue("price"))
+		// like VK, missing fields are kept
+		r.ParseForm()
+		if _, ok := r.Form["old_price"]; ok {
+			item.Price.OldAmount = amount(r.FormValue("old_price"))
+		}
+		if _, ok := r.Form["url"]; ok {
+			item.URL = r.FormValue("url")
+		}
+	}
+
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/market.get":
+			var items []*MarketItemObject
+			for id := 0; id <= s.nextID; id++ {
+				if item, ok := s.items[id]; ok {
+					items = append(items, item)
+				}
+			}
+			respond(w, map[string]interface{}{"count": len(items), "items": items})
+		case "/photos.getMarketUploadServer":
+			if r.FormValue("group_id") != "1" {
+				t.Errorf("upload server for group %s", r.FormValue("group_id"))
+			}
+			s.uploads = append(s.uploads, r.FormValue("main_photo"))
+			respond(w, map[string]string{"upload_url": s.URL + "/upload"})
+		case "/upload":
+			_, header, err := r.FormFile("file")
+			if err != nil {
+				t.Errorf("upload: %v", err)
+				return
+			}
+			fmt.Fprintf(w, `{"server": 1, "photo": "%s", "hash": "h"}`, header.Filename)
+		case "/photos.saveMarketPhoto":
+			s.nextID++
+			respond(w, []map[string]interface{}{{"id": 200 + s.nextID, "text": r.FormValue("photo")}})
+		case "/market.add":
+			s.nextID++
+			item := &MarketItemObject{ID: s.nextID, OwnerID: -1}
+			update(item, r)
+			s.items[item.ID] = item
+			respond(w, map[string]int{"market_item_id": item.ID})
+		case "/market.edit":
+			r.ParseForm()
+			s.edits = append(s.edits, r.Form)
+			id, _ := strconv.Atoi(r.FormValue("item_id"))
+			update(s.items[id], r)
+			respond(w, 1)
+		case "/market.delete":
+			id, _ := strconv.Atoi(r.FormValue("item_id"))
+			if s.failDelete[id] {
+				fmt.Fprint(w, `{"error": {"error_code": 1403, "error_msg": "Access denied"}}`)
+				return
+			}
+			delete(s.items, id)
+			respond(w, 1)
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+		}
+	}))
+	return s
+}
+
+func TestCatalogApply(t *testing.T) {
+	dir := t.TempDir()
+	photo := func(name string) string {
+		path := filepath.Join(dir, name)
+		writeFile(t, path, "image "+name)
+		return path
+	}
+	a, b, c := photo("a.jpg"), photo("b.jpg"), photo("c.jpg")
+	checksumA, err := fileChecksum(a)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A-1 had a discount and a link which are gone from the feed
+	shop := newShopServer(t,
+		MarketItemObject{ID: 10, Title: "Mug", URL: "https://example.com/mug", Price: MarketPrice{Amount: "1000", OldAmount: "2500"}},
+		MarketItemObject{ID: 12, Title: "Removed"},
+		MarketItemObject{ID: 14, Title: "Locked"},
+	)
+	defer shop.Close()
+	shop.failDelete[14] = true
+	vk := WithToken("token")
+	vk.ApiUrl = shop.URL + "/"
+
+	statePath := filepath.Join(dir, "state.json")
+	state := &CatalogState{Items: map[string]CatalogStateItem{
+		"A-1":    {ItemID: 10, Photos: []CatalogStatePhoto{{Checksum: checksumA, PhotoID: 100}}},
+		"OLD":    {ItemID: 12},
+		"LOCKED": {ItemID: 14},
+	}}
+	err = state.Save(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sync := NewCatalogSync(vk, 1, statePath)
+	sync.DeleteMissing = true
+	sync.RequestsPerSecond = 100
+	feed := []CatalogItem{
+		{SKU: "A-1", Name: "Mug", Price: 10, Photos: []string{a, b}},
+		{SKU: "C-3", Name: "Plate", Price: 3.5, OldPrice: 4, URL: "https://example.com/plate", Photos: []string{c}},
+	}
+	plan, err := sync.Plan(feed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var actions []string
+	for _, action := range plan.Actions {
+		actions = append(actions, action.String())
+	}
+	want := []string{
+		"update A-1 (item 10): old_price, url, photos, 1 new photos",
+		"create C-3, 1 photos",
+		"delete OLD (item 12)",
+		"delete LOCKED (item 14)",
+	}
+	if !reflect.DeepEqual(actions, want) {
+		t.Fatalf("got actions %q, want %q", actions, want)
+	}
+
+	report, err := sync.Apply(plan)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Created != 1 || report.Updated != 1 || report.Deleted != 1 || report.PhotosUploaded != 2 {
+		t.Errorf("got report %+v", report)
+	}
+	if len(report.Failed) != 1 || report.Failed[0].Action.SKU != "LOCKED" {
+		t.Errorf("got failed %+v", report.Failed)
+	}
+	// b is an extra photo of A-1, c is the main photo of C-3
+	if !reflect.DeepEqual(shop.uploads, []string{"0", "1"}) {
+		t.Errorf("got uploads %v", shop.uploads)
+	}
+	if len(shop.edits) != 1 || shop.edits[0].Get("old_price") != "0" || shop.edits[0].Get("url") != "" ||
+		shop.edits[0].Get("main_photo_id") != "100" || shop.edits[0].Get("photo_ids") != "221" {
+		t.Errorf("got edits %v", shop.edits)
+	}
+
+	saved, err := LoadCatalogState(statePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checksumB, _ := fileChecksum(b)
+	checksumC, _ := fileChecksum(c)
+	wantState := map[string]CatalogStateItem{
+		"A-1":    {ItemID: 10, Photos: []CatalogStatePhoto{{Checksum: checksumA, PhotoID: 100}, {Checksum: checksumB, PhotoID: 221}}},
+		"C-3":    {ItemID: 23, Photos: []CatalogStatePhoto{{Checksum: checksumC, PhotoID: 222}}},
+		"LOCKED": {ItemID: 14},
+	}
+	if !reflect.DeepEqual(saved.Items, wantState) {
+		t.Errorf("got state %+v, want %+v", saved.Items, wantState)
+	}
+
+	// the shop is in line with the feed except the failed deletion
+	plan, err = sync.Plan(feed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.Actions) != 1 || plan.Actions[0].String() != "delete LOCKED (item 14)" || plan.Unchanged != 2 {
+		t.Errorf("got %d unchanged and actions %+v", plan.Unchanged, plan.Actions)
+	}
+}
@@ -16,3 +16,25 @@ type Error struct {
 func (e *Error) Error() string {
 	return fmt.Sprintf("code %d: %s", e.Code, e.Message)
 }
+
+// An ExecuteCallError describes an API call failed within execute.
+type ExecuteCallError struct {
+	Method  string `json:"method"`
+	Code    int    `json:"error_code"`
+	Message string `json:"error_msg"`
+}
+
+// An ExecuteError is returned by execute if some
+// of the API calls within the code have failed.
+// Response holds the result of the code,
+// failed calls return false in it.
+// https://vk.com/dev/execute
+type ExecuteError struct {
+	Errors   []ExecuteCallError
+	Response []byte
+}
+
+func (e *ExecuteError) Error() string {
+	first := e.Errors[0]
+	return fmt.Sprintf("execute: %s: code %d: %s", first.Method, first.Code, first.Message)
+}
@@ -0,0 +1,299 @@
+package easyvk
+
+import (
+	"reflect"
+	"strings"
+)
+
+// UserField is an optional field of the user
+// which can be requested in users.get and similar methods.
+// https://vk.com/dev/objects/user
+type UserField string
+
+const (
+	UserFieldAbout                  UserField = "about"
+	UserFieldActivities             UserField = "activities"
+	UserFieldBdate                  UserField = "bdate"
+	UserFieldBlacklisted            UserField = "blacklisted"
+	UserFieldBlacklistedByMe        UserField = "blacklisted_by_me"
+	UserFieldBooks                  UserField = "books"
+	UserFieldCanPost                UserField = "can_post"
+	UserFieldCanSeeAllPosts         UserField = "can_see_all_posts"
+	UserFieldCanSeeAudio            UserField = "can_see_audio"
+	UserFieldCanSendFriendRequest   UserField = "can_send_friend_request"
+	UserFieldCanWritePrivateMessage UserField = "can_write_private_message"
+	UserFieldCareer                 UserField = "career"
+	UserFieldCity                   UserField = "city"
+	UserFieldCommonCount            UserField = "common_count"
+	UserFieldConnections            UserField = "connections"
+	UserFieldContacts               UserField = "contacts"
+	UserFieldCounters               UserField = "counters"
+	UserFieldCountry                UserField = "country"
+	UserFieldCropPhoto              UserField = "crop_photo"
+	UserFieldDomain                 UserField = "domain"
+	UserFieldEducation              UserField = "education"
+	UserFieldExports                UserField = "exports"
+	UserFieldFollowersCount         UserField = "followers_count"
+	UserFieldFriendStatus           UserField = "friend_status"
+	UserFieldGames                  UserField = "games"
+	UserFieldHasMobile              UserField = "has_mobile"
+	UserFieldHasPhoto               UserField = "has_photo"
+	UserFieldHomeTown               UserField = "home_town"
+	UserFieldInterests              UserField = "interests"
+	UserFieldIsFavorite             UserField = "is_favorite"
+	UserFieldIsFriend               UserField = "is_friend"
+	UserFieldIsHiddenFromFeed       UserField = "is_hidden_from_feed"
+	UserFieldLastSeen               UserField = "last_seen"
+	UserFieldMaidenName             UserField = "maiden_name"
+	UserFieldMilitary               UserField = "military"
+	UserFieldMovies                 UserField = "movies"
+	UserFieldMusic                  UserField = "music"
+	UserFieldNickname               UserField = "nickname"
+	UserFieldOccupation             UserField = "occupation"
+	UserFieldOnline                 UserField = "online"
+	UserFieldPersonal               UserField = "personal"
+	UserFieldPhoto50                UserField = "photo_50"
+	UserFieldPhoto100               UserField = "photo_100"
+	UserFieldPhoto200               UserField = "photo_200"
+	UserFieldPhoto200Orig           UserField = "photo_200_orig"
+	UserFieldPhoto400Orig           UserField = "photo_400_orig"
+	UserFieldPhotoID                UserField = "photo_id"
+	UserFieldPhotoMax               UserField = "photo_max"
+	UserFieldPhotoMaxOrig           UserField = "photo_max_orig"
+	UserFieldQuotes                 UserField = "quotes"
+	UserFieldRelation               UserField = "relation"
+	UserFieldRelatives              UserField = "relatives"
+	UserFieldSchools                UserField = "schools"
+	UserFieldScreenName             UserField = "screen_name"
+	UserFieldSex                    UserField = "sex"
+	UserFieldSite                   UserField = "site"
+	UserFieldStatus                 UserField = "status"
+	UserFieldTimezone               UserField = "timezone"
+	UserFieldTV                     UserField = "tv"
+	UserFieldUniversities           UserField = "universities"
+	UserFieldVerified               UserField = "verified"
+	UserFieldWallComments           UserField = "wall_comments"
+)
+
+// userFieldKeys maps keys of the response
+// which are filled by another field.
+var userFieldKeys = map[string]UserField{
+	"mobile_phone":     UserFieldContacts,
+	"home_phone":       UserFieldContacts,
+	"university":       UserFieldEducation,
+	"university_name":  UserFieldEducation,
+	"faculty":          UserFieldEducation,
+	"faculty_name":     UserFieldEducation,
+	"graduation":       UserFieldEducation,
+	"skype":            UserFieldConnections,
+	"facebook":         UserFieldConnections,
+	"twitter":          UserFieldConnections,
+	"livejournal":      UserFieldConnections,
+	"instagram":        UserFieldConnections,
+	"relation_partner": UserFieldRelation,
+}
+
+var knownUserFields = map[UserField]bool{}
+
+func init() {
+	for _, f := range []UserField{
+		UserFieldAbout, UserFieldActivities, UserFieldBdate, UserFieldBlacklisted,
+		UserFieldBlacklistedByMe, UserFieldBooks, UserFieldCanPost, UserFieldCanSeeAllPosts,
+		UserFieldCanSeeAudio, UserFieldCanSendFriendRequest, UserFieldCanWritePrivateMessage,
+		UserFieldCareer, UserFieldCity, UserFieldCommonCount, UserFieldConnections,
+		UserFieldContacts, UserFieldCounters, UserFieldCountry, UserFieldCropPhoto,
+		UserFieldDomain, UserFieldEducation, UserFieldExports, UserFieldFollowersCount,
+		UserFieldFriendStatus, UserFieldGames, UserFieldHasMobile, UserFieldHasPhoto,
+		UserFieldHomeTown, UserFieldInterests, UserFieldIsFavorite, UserFieldIsFriend,
+		UserFieldIsHiddenFromFeed, UserFieldLastSeen, UserFieldMaidenName, UserFieldMilitary,
+		UserFieldMovies, UserFieldMusic, UserFieldNickname, UserFieldOccupation,
+		UserFieldOnline, UserFieldPersonal, UserFieldPhoto50, UserFieldPhoto100,
+		UserFieldPhoto200, UserFieldPhoto200Orig, UserFieldPhoto400Orig, UserFieldPhotoID,
+		UserFieldPhotoMax, UserFieldPhotoMaxOrig, UserFieldQuotes, UserFieldRelation,
+		UserFieldRelatives, UserFieldSchools, UserFieldScreenName, UserFieldSex,
+		UserFieldSite, UserFieldStatus, UserFieldTimezone, UserFieldTV,
+		UserFieldUniversities, UserFieldVerified, UserFieldWallComments,
+	} {
+		knownUserFields[f] = true
+	}
+}
+
+// UserFields is a set of user fields.
+type UserFields []UserField
+
+// NewUserFields returns a set of the given fields.
+func NewUserFields(fields ...UserField) UserFields {
+	return UserFields(nil).Add(fields...)
+}
+
+// Add returns the set with the given fields added.
+func (f UserFields) Add(fields ...UserField) UserFields {
+	for _, field := range fields {
+		if !f.Has(field) {
+			f = append(f, field)
+		}
+	}
+	return f
+}
+
+// Has reports whether the set contains the field.
+func (f UserFields) Has(field UserField) bool {
+	for _, x := range f {
+		if x == field {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns fields as a list like "sex,bdate".
+func (f UserFields) String() string {
+	names := make([]string, len(f))
+	for i, field := range f {
+		names[i] = string(field)
+	}
+	return strings.Join(names, ",")
+}
+
+// UserFieldsOf returns fields needed to fill json tags of the struct v.
+// v may be a struct, a pointer to a struct or a slice of them.
+// Tags which are not user fields, like id or first_name, are skipped.
+func UserFieldsOf(v interface{}) UserFields {
+	var fields UserFields
+	for _, key := range jsonKeys(reflect.TypeOf(v)) {
+		if field, ok := userFieldKeys[key]; ok {
+			fields = fields.Add(field)
+		} else if knownUserFields[UserField(key)] {
+			fields = fields.Add(UserField(key))
+		}
+	}
+	return fields
+}
+
+// GroupField is an optional field of the community
+// which can be requested in groups.getById and similar methods.
+// https://vk.com/dev/objects/group
+type GroupField string
+
+const (
+	GroupFieldActivity       GroupField = "activity"
+	GroupFieldAddresses      GroupField = "addresses"
+	GroupFieldAgeLimits      GroupField = "age_limits"
+	GroupFieldBanInfo        GroupField = "ban_info"
+	GroupFieldCanCreateTopic GroupField = "can_create_topic"
+	GroupFieldCanMessage     GroupField = "can_message"
+	GroupFieldCanPost        GroupField = "can_post"
+	GroupFieldCanSeeAllPosts GroupField = "can_see_all_posts"
+	GroupFieldCity           GroupField = "city"
+	GroupFieldContacts       GroupField = "contacts"
+	GroupFieldCounters       GroupField = "counters"
+	GroupFieldCountry        GroupField = "country"
+	GroupFieldCover          GroupField = "cover"
+	GroupFieldDescription    GroupField = "description"
+	GroupFieldFinishDate     GroupField = "finish_date"
+	GroupFieldFixedPost      GroupField = "fixed_post"
+	GroupFieldIsFavorite     GroupField = "is_favorite"
+	GroupFieldLinks          GroupField = "links"
+	GroupFieldMainAlbumID    GroupField = "main_album_id"
+	GroupFieldMarket         GroupField = "market"
+	GroupFieldMembersCount   GroupField = "members_count"
+	GroupFieldSite           GroupField = "site"
+	GroupFieldStartDate      GroupField = "start_date"
+	GroupFieldStatus         GroupField = "status"
+	GroupFieldVerified       GroupField = "verified"
+)
+
+var knownGroupFields = map[GroupField]bool{}
+
+func init() {
+	for _, f := range []GroupField{
+		GroupFieldActivity, GroupFieldAddresses, GroupFieldAgeLimits, GroupFieldBanInfo,
+		GroupFieldCanCreateTopic, GroupFieldCanMessage, GroupFieldCanPost,
+		GroupFieldCanSeeAllPosts, GroupFieldCity, GroupFieldContacts, GroupFieldCounters,
+		GroupFieldCountry, GroupFieldCover, GroupFieldDescription, GroupFieldFinishDate,
+		GroupFieldFixedPost, GroupFieldIsFavorite, GroupFieldLinks, GroupFieldMainAlbumID,
+		GroupFieldMarket, GroupFieldMembersCount, GroupFieldSite, GroupFieldStartDate,
+		GroupFieldStatus, GroupFieldVerified,
+	} {
+		knownGroupFields[f] = true
+	}
+}
+
+// GroupFields is a set of community fields.
+type GroupFields []GroupField
+
+// NewGroupFields returns a set of the given fields.
+func NewGroupFields(fields ...GroupField) GroupFields {
+	return GroupFields(nil).Add(fields...)
+}
+
+// Add returns the set with the given fields added.
+func (f GroupFields) Add(fields ...GroupField) GroupFields {
+	for _, field := range fields {
+		if !f.Has(field) {
+			f = append(f, field)
+		}
+	}
+	return f
+}
+
+// Has reports whether the set contains the field.
+func (f GroupFields) Has(field GroupField) bool {
+	for _, x := range f {
+		if x == field {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns fields as a list like "description,cover".
+func (f GroupFields) String() string {
+	names := make([]string, len(f))
+	for i, field := range f {
+		names[i] = string(field)
+	}
+	return strings.Join(names, ",")
+}
+
+// GroupFieldsOf returns fields needed to fill json tags of the struct v.
+// v may be a struct, a pointer to a struct or a slice of them.
+func GroupFieldsOf(v interface{}) GroupFields {
+	var fields GroupFields
+	for _, key := range jsonKeys(reflect.TypeOf(v)) {
+		if knownGroupFields[GroupField(key)] {
+			fields = fields.Add(GroupField(key))
+		}
+	}
+	return fields
+}
+
+// jsonKeys returns json keys of the struct fields,
+// including fields of embedded structs.
+func jsonKeys(t reflect.Type) []string {
+	for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var keys []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name == "-" {
+			continue
+		}
+		if field.Anonymous && name == "" {
+			keys = append(keys, jsonKeys(field.Type)...)
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		keys = append(keys, name)
+	}
+	return keys
+}
@@ -0,0 +1,74 @@
+package easyvk
+
+import (
+	"reflect"
+	"testing"
+)
+
+type fieldsEmbedded struct {
+	Sex Sex `json:"sex"`
+}
+
+type fieldsUser struct {
+	fieldsEmbedded
+	ID        int      `json:"id"`
+	FirstName string   `json:"first_name"`
+	City      string   `json:"city,omitempty"`
+	Phone     string   `json:"mobile_phone"`
+	Twitter   string   `json:"twitter"`
+	Partner   int      `json:"relation_partner"`
+	Seen      UnixTime `json:"last_seen"`
+	Ignored   string   `json:"-"`
+	Untagged  string
+}
+
+func TestUserFieldsOf(t *testing.T) {
+	want := UserFields{UserFieldSex, UserFieldCity, UserFieldContacts, UserFieldConnections, UserFieldRelation, UserFieldLastSeen}
+	tests := []struct {
+		name string
+		v    interface{}
+		want UserFields
+	}{
+		{"struct", fieldsUser{}, want},
+		{"pointer", &fieldsUser{}, want},
+		{"slice", []*fieldsUser{}, want},
+		{"no fields", struct {
+			ID int `json:"id"`
+		}{}, nil},
+		{"not a struct", 1, nil},
+		{"nil", nil, nil},
+	}
+	for _, tt := range tests {
+		got := UserFieldsOf(tt.v)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if s := UserFieldsOf([]UserObject{}).String(); s == "" {
+		t.Error("no fields of UserObject")
+	}
+}
+
+func TestUserFields(t *testing.T) {
+	fields := NewUserFields(UserFieldSex, UserFieldCity, UserFieldSex)
+	fields = fields.Add(UserFieldPhoto50, UserFieldCity)
+	if got := fields.String(); got != "sex,city,photo_50" {
+		t.Errorf("got %q", got)
+	}
+	if !fields.Has(UserFieldPhoto50) || fields.Has(UserFieldBdate) {
+		t.Error("wrong Has")
+	}
+}
+
+func TestGroupFieldsOf(t *testing.T) {
+	got := GroupFieldsOf(struct {
+		ID      int    `json:"id"`
+		City    string `json:"city"`
+		Members int    `json:"members_count"`
+	}{})
+	want := GroupFields{GroupFieldCity, GroupFieldMembersCount}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
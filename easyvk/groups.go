@@ -16,10 +16,10 @@ type GroupsByIdResponse []GroupObject
 
 // GetById returns information about communities by their IDs.
 // https://vk.com/dev/groups.getById
-func (g *Groups) GetById(groupIds []int, fields []string) (GroupsByIdResponse, error) {
+func (g *Groups) GetById(groupIds []int, fields GroupFields) (GroupsByIdResponse, error) {
 	params := map[string]string{
 		"group_ids": strings.Join(intIdsToString(groupIds), ","),
-		"fields":    strings.Join(fields, ","),
+		"fields":    fields.String(),
 	}
 	resp, err := g.vk.Request("groups.getById", params)
 	if err != nil {
@@ -127,7 +127,7 @@ type GetMembersInfoParams struct {
 	Sort    string
 	Offset  int
 	Count   int
-	Fields  string
+	Fields  UserFields
 	Filter  string
 }
 
@@ -144,15 +144,15 @@ func (g *Groups) GetMembersInfo(p GetMembersInfoParams) (*GetMembersInfoResponse
 		count = p.Count
 	}
 	// set field for return user object not id
-	if p.Fields == "" {
-		p.Fields = "photo_50"
+	if len(p.Fields) == 0 {
+		p.Fields = UserFields{UserFieldPhoto50}
 	}
 	params := map[string]string{
 		"group_id": strconv.Itoa(p.GroupId),
 		"sort":     p.Sort,
 		"offset":   strconv.Itoa(p.Offset),
 		"count":    strconv.Itoa(count),
-		"fields":   p.Fields,
+		"fields":   p.Fields.String(),
 		"filter":   p.Filter,
 	}
 	resp, err := g.vk.Request("groups.getMembers", params)
@@ -194,6 +194,32 @@ func (g *Groups) GetCallbackConfirmationCode(groupId int) (*GetCallbackConfirmat
 	return res, nil
 }
 
+// GroupsGetLongPollServerResponse describes the server of Bots Long Poll API.
+// https://vk.com/dev/groups.getLongPollServer
+type GroupsGetLongPollServerResponse struct {
+	Key    string `json:"key"`
+	Server string `json:"server"`
+	TS     string `json:"ts"`
+}
+
+// GetLongPollServer returns data to connect to Bots Long Poll API.
+// https://vk.com/dev/groups.getLongPollServer
+func (g *Groups) GetLongPollServer(groupId int) (*GroupsGetLongPollServerResponse, error) {
+	params := map[string]string{
+		"group_id": strconv.Itoa(groupId),
+	}
+	resp, err := g.vk.Request("groups.getLongPollServer", params)
+	if err != nil {
+		return nil, err
+	}
+	var server GroupsGetLongPollServerResponse
+	err = json.Unmarshal(resp, &server)
+	if err != nil {
+		return nil, err
+	}
+	return &server, nil
+}
+
 // https://vk.com/dev/groups.getCallbackServers
 type GetCallbackServersResponse struct {
 	Count int              `json:"count"`
@@ -290,71 +316,61 @@ func (g *Groups) EditCallbackServer(groupId, serverId int, url, title, secretKey
 	return res, nil
 }
 
-// https://vk.com/dev/groups.setCallbackSettings
-type SetCallbackSettingsResponse int
-
-var knowEvents = []string{
-	"message_new",
-	"message_reply",
-	"message_allow",
-	"message_deny",
-	"photo_new",
-	"audio_new",
-	"video_new",
-	"wall_reply_new",
-	"wall_reply_edit",
-	"wall_reply_delete",
-	"wall_reply_restore",
-	"wall_post_new",
-	"wall_repost",
-	"board_post_new",
-	"board_post_edit",
-	"board_post_restore",
-	"board_post_delete",
-	"photo_comment_new",
-	"photo_comment_edit",
-	"photo_comment_delete",
-	"photo_comment_restore",
-	"video_comment_new",
-	"video_comment_edit",
-	"video_comment_delete",
-	"video_comment_restore",
-	"market_comment_new",
-	"market_comment_edit",
-	"market_comment_delete",
-	"market_comment_restore",
-	"poll_vote_new",
-	"group_join",
-	"group_leave",
-	"group_officers_edit",
-	"group_change_settings",
-	"group_change_photo",
-	"user_block",
-	"user_unblock",
+// CallbackSettings describes which events the Callback API server gets.
+// https://vk.com/dev/groups.getCallbackSettings
+type CallbackSettings struct {
+	APIVersion string
+	Events     map[CallbackEventType]bool
 }
 
-// https://vk.com/dev/groups.setCallbackSettings
-func (g *Groups) SetCallbackSettings(groupId, serverId int, enableEvents []string) (SetCallbackSettingsResponse, error) {
+// GetCallbackSettings returns Callback API notifications settings of the server.
+// https://vk.com/dev/groups.getCallbackSettings
+func (g *Groups) GetCallbackSettings(groupId, serverId int) (*CallbackSettings, error) {
 	params := map[string]string{
 		"group_id":  strconv.Itoa(groupId),
 		"server_id": strconv.Itoa(serverId),
 	}
+	resp, err := g.vk.Request("groups.getCallbackSettings", params)
+	if err != nil {
+		return nil, err
+	}
 
-	var enabled = func(event string) bool {
-		for _, ev := range enableEvents {
-			if ev == event {
-				return true
-			}
-		}
-		return false
+	var raw struct {
+		APIVersion string                    `json:"api_version"`
+		Events     map[CallbackEventType]int `json:"events"`
+	}
+	err = json.Unmarshal(resp, &raw)
+	if err != nil {
+		return nil, err
 	}
 
-	for _, event := range knowEvents {
-		if enabled(event) {
-			params[event] = "1"
-		} else {
-			params[event] = "0"
-		}
+	res := &CallbackSettings{
+		APIVersion: raw.APIVersion,
+		Events:     map[CallbackEventType]bool{},
+	}
+	for event, on := range raw.Events {
+		res.Events[event] = on == 1
+	}
+
+	return res, nil
+}
+
+// https://vk.com/dev/groups.setCallbackSettings
+type SetCallbackSettingsResponse int
+
+// SetCallbackSettings changes only the events which are in s.Events,
+// other events stay as they are. API version is changed if s.APIVersion is set.
+// https://vk.com/dev/groups.setCallbackSettings
+func (g *Groups) SetCallbackSettings(groupId, serverId int, s CallbackSettings) (SetCallbackSettingsResponse, error) {
+	params := map[string]string{
+		"group_id":  strconv.Itoa(groupId),
+		"server_id": strconv.Itoa(serverId),
+	}
+	if s.APIVersion != "" {
+		params["api_version"] = s.APIVersion
+	}
+	for event, on := range s.Events {
+		params[string(event)] = boolConverter(on)
 	}
 
 	resp, err := g.vk.Request("groups.setCallbackSettings", params)
@@ -371,17 +387,76 @@ func (g *Groups) SetCallbackSettings(groupId, serverId int, enableEvents []strin
 	return res, nil
 }
 
+// UpdateCallbackSettings reads the current settings of the server,
+// lets update change them and sends only what has been changed.
+// Returns the resulting settings.
+func (g *Groups) UpdateCallbackSettings(groupId, serverId int, update func(s *CallbackSettings)) (*CallbackSettings, error) {
+	current, err := g.GetCallbackSettings(groupId, serverId)
+	if err != nil {
+		return nil, err
+	}
+
+	updated := &CallbackSettings{
+		APIVersion: current.APIVersion,
+		Events:     map[CallbackEventType]bool{},
+	}
+	for event, on := range current.Events {
+		updated.Events[event] = on
+	}
+	update(updated)
+
+	patch := CallbackSettings{Events: map[CallbackEventType]bool{}}
+	if updated.APIVersion != current.APIVersion {
+		patch.APIVersion = updated.APIVersion
+	}
+	for event, on := range updated.Events {
+		if current.Events[event] != on {
+			patch.Events[event] = on
+		}
+	}
+	if patch.APIVersion == "" && len(patch.Events) == 0 {
+		return updated, nil
+	}
+
+	_, err = g.SetCallbackSettings(groupId, serverId, patch)
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// BanReason is a reason of the ban in the community.
+// https://vk.com/dev/groups.banUser
+type BanReason int
+
+const (
+	BanReasonOther BanReason = iota
+	BanReasonSpam
+	BanReasonInsult
+	BanReasonObscene
+	BanReasonOffTopic
+)
+
+func (r BanReason) String() string {
+	switch r {
+	case BanReasonSpam:
+		return "spam"
+	case BanReasonInsult:
+		return "insult"
+	case BanReasonObscene:
+		return "obscene"
+	case BanReasonOffTopic:
+		return "off topic"
+	}
+	return "other"
+}
+
 type BanUserParams struct {
 	GroupID int
 	UserID  int
-	EndDate time.Time
-	//причина бана:
-	//0 — другое (по умолчанию);
-	//1 — спам;
-	//2 — оскорбление участников;
-	//3 — нецензурные выражения;
-	//4 — сообщения не по теме.
-	Reason         int
+	// Zero for the permanent ban.
+	EndDate        time.Time
+	Reason         BanReason
 	Comment        string
 	CommentVisible bool
 }
@@ -391,20 +466,576 @@ func (g *Groups) BanUser(p BanUserParams) (bool, error) {
 	params := map[string]string{
 		"group_id":        strconv.Itoa(p.GroupID),
 		"user_id":         strconv.Itoa(p.UserID),
-		"end_date":        strconv.Itoa(int(p.EndDate.Unix())),
-		"reason":          strconv.Itoa(p.Reason),
+		"reason":          strconv.Itoa(int(p.Reason)),
 		"comment":         p.Comment,
 		"comment_visible": boolConverter(p.CommentVisible),
 	}
+	if !p.EndDate.IsZero() {
+		params["end_date"] = strconv.Itoa(int(p.EndDate.Unix()))
+	}
+
+	return g.vk.requestBool("groups.banUser", params)
+}
+
+// GroupsGetParams provides fields for Get params.
+// https://vk.com/dev/groups.get
+type GroupsGetParams struct {
+	UserID int
+	// comma separated list of: admin, editor, moder, groups, publics, events, hasAddress
+	Filter string
+	Fields GroupFields
+	Offset int
+	Count  int
+}
+
+// GroupsGetResponse describes a list of communities.
+// https://vk.com/dev/groups.get
+type GroupsGetResponse struct {
+	Count int           `json:"count"`
+	Items []GroupObject `json:"items"`
+}
+
+// Get returns a list of the communities to which a user belongs.
+// https://vk.com/dev/groups.get
+func (g *Groups) Get(p GroupsGetParams) (*GroupsGetResponse, error) {
+	// set default count
+	count := 1000
+	if p.Count != 0 {
+		count = p.Count
+	}
+	params := map[string]string{
+		"extended": "1",
+		"filter":   p.Filter,
+		"fields":   p.Fields.String(),
+		"offset":   strconv.Itoa(p.Offset),
+		"count":    strconv.Itoa(count),
+	}
+	if p.UserID != 0 {
+		params["user_id"] = strconv.Itoa(p.UserID)
+	}
+	resp, err := g.vk.Request("groups.get", params)
+	if err != nil {
+		return nil, err
+	}
+	res := &GroupsGetResponse{}
+	err = json.Unmarshal(resp, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GroupsSearchParams provides fields for Search params.
+// https://vk.com/dev/groups.search
+type GroupsSearchParams struct {
+	Q string
+	// one of: group, page, event
+	Type      string
+	CountryID int
+	CityID    int
+	Future    bool
+	Market    bool
+	// 0 — default, 1 — by growth speed, 2 — by day attendance,
+	// 3 — by likes, 4 — by comments, 5 — by board entries
+	Sort   int
+	Offset int
+	Count  int
+}
+
+// GroupsSearchResponse describes a list of found communities.
+// https://vk.com/dev/groups.search
+type GroupsSearchResponse struct {
+	Count int           `json:"count"`
+	Items []GroupObject `json:"items"`
+}
+
+// Search returns a list of communities matching the search criteria.
+// https://vk.com/dev/groups.search
+func (g *Groups) Search(p GroupsSearchParams) (*GroupsSearchResponse, error) {
+	// set default count
+	count := 20
+	if p.Count != 0 {
+		count = p.Count
+	}
+	params := map[string]string{
+		"q":      p.Q,
+		"type":   p.Type,
+		"future": boolConverter(p.Future),
+		"market": boolConverter(p.Market),
+		"sort":   strconv.Itoa(p.Sort),
+		"offset": strconv.Itoa(p.Offset),
+		"count":  strconv.Itoa(count),
+	}
+	if p.CountryID != 0 {
+		params["country_id"] = strconv.Itoa(p.CountryID)
+	}
+	if p.CityID != 0 {
+		params["city_id"] = strconv.Itoa(p.CityID)
+	}
+	resp, err := g.vk.Request("groups.search", params)
+	if err != nil {
+		return nil, err
+	}
+	res := &GroupsSearchResponse{}
+	err = json.Unmarshal(resp, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
 
-	resp, err := g.vk.Request("groups.banUser", params)
+// GroupsGetSettingsResponse describes settings of the community.
+// https://vk.com/dev/groups.getSettings
+type GroupsGetSettingsResponse struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Address     string `json:"address"`
+	Place       struct {
+		ID        int     `json:"id"`
+		Title     string  `json:"title"`
+		Latitude  float64 `json:"latitude"`
+		Longitude float64 `json:"longitude"`
+		Address   string  `json:"address"`
+	} `json:"place"`
+	// 0 — open, 1 — closed, 2 — private
+	Access      int `json:"access"`
+	Subject     int `json:"subject"`
+	SubjectList []struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"subject_list"`
+	Website string `json:"website"`
+	RSS     string `json:"rss"`
+	Phone   string `json:"phone"`
+	Email   string `json:"email"`
+	// 0 — disabled, 1 — open, 2 — limited, 3 — closed
+	Wall int `json:"wall"`
+	// 0 — disabled, 1 — open, 2 — limited
+	Topics    int `json:"topics"`
+	Photos    int `json:"photos"`
+	Video     int `json:"video"`
+	Audio     int `json:"audio"`
+	Docs      int `json:"docs"`
+	Wiki      int `json:"wiki"`
+	Links     int `json:"links"`
+	Events    int `json:"events"`
+	Places    int `json:"places"`
+	Contacts  int `json:"contacts"`
+	Messages  int `json:"messages"`
+	Articles  int `json:"articles"`
+	Addresses int `json:"addresses"`
+	// 1 — no limits, 2 — 16+, 3 — 18+
+	AgeLimits        int      `json:"age_limits"`
+	ObsceneFilter    int      `json:"obscene_filter"`
+	ObsceneStopwords int      `json:"obscene_stopwords"`
+	ObsceneWords     []string `json:"obscene_words"`
+	Market           struct {
+		Enabled         int `json:"enabled"`
+		CommentsEnabled int `json:"comments_enabled"`
+		ContactID       int `json:"contact_id"`
+		Currency        struct {
+			ID   int    `json:"id"`
+			Name string `json:"name"`
+		} `json:"currency"`
+	} `json:"market"`
+}
+
+// GetSettings returns community settings.
+// https://vk.com/dev/groups.getSettings
+func (g *Groups) GetSettings(groupId int) (*GroupsGetSettingsResponse, error) {
+	params := map[string]string{
+		"group_id": strconv.Itoa(groupId),
+	}
+	resp, err := g.vk.Request("groups.getSettings", params)
 	if err != nil {
-		return false, err
+		return nil, err
+	}
+	res := &GroupsGetSettingsResponse{}
+	err = json.Unmarshal(resp, res)
+	if err != nil {
+		return nil, err
 	}
 
-	ok, err := strconv.ParseUint(string(resp), 10, 8)
+	return res, nil
+}
+
+// GroupsEditParams provides fields for Edit params.
+// Only non-nil fields are changed.
+// https://vk.com/dev/groups.edit
+type GroupsEditParams struct {
+	GroupID     int
+	Title       *string
+	Description *string
+	ScreenName  *string
+	// 0 — open, 1 — closed, 2 — private
+	Access  *int
+	Website *string
+	Subject *int
+	Email   *string
+	Phone   *string
+	RSS     *string
+	// 0 — disabled, 1 — open, 2 — limited, 3 — closed
+	Wall *int
+	// 0 — disabled, 1 — open, 2 — limited
+	Topics    *int
+	Photos    *int
+	Video     *int
+	Audio     *int
+	Docs      *int
+	Wiki      *int
+	Links     *bool
+	Events    *bool
+	Places    *bool
+	Contacts  *bool
+	Messages  *bool
+	Articles  *bool
+	Addresses *bool
+	// 1 — no limits, 2 — 16+, 3 — 18+
+	AgeLimits        *int
+	Market           *bool
+	ObsceneFilter    *bool
+	ObsceneStopwords *bool
+	ObsceneWords     []string
+}
+
+// Edit edits a community.
+// https://vk.com/dev/groups.edit
+func (g *Groups) Edit(p GroupsEditParams) (bool, error) {
+	params := map[string]string{
+		"group_id": strconv.Itoa(p.GroupID),
+	}
+	setString := func(name string, v *string) {
+		if v != nil {
+			params[name] = *v
+		}
+	}
+	setInt := func(name string, v *int) {
+		if v != nil {
+			params[name] = strconv.Itoa(*v)
+		}
+	}
+	setBool := func(name string, v *bool) {
+		if v != nil {
+			params[name] = boolConverter(*v)
+		}
+	}
+	setString("title", p.Title)
+	setString("description", p.Description)
+	setString("screen_name", p.ScreenName)
+	setInt("access", p.Access)
+	setString("website", p.Website)
+	setInt("subject", p.Subject)
+	setString("email", p.Email)
+	setString("phone", p.Phone)
+	setString("rss", p.RSS)
+	setInt("wall", p.Wall)
+	setInt("topics", p.Topics)
+	setInt("photos", p.Photos)
+	setInt("video", p.Video)
+	setInt("audio", p.Audio)
+	setInt("docs", p.Docs)
+	setInt("wiki", p.Wiki)
+	setBool("links", p.Links)
+	setBool("events", p.Events)
+	setBool("places", p.Places)
+	setBool("contacts", p.Contacts)
+	setBool("messages", p.Messages)
+	setBool("articles", p.Articles)
+	setBool("addresses", p.Addresses)
+	setInt("age_limits", p.AgeLimits)
+	setBool("market", p.Market)
+	setBool("obscene_filter", p.ObsceneFilter)
+	setBool("obscene_stopwords", p.ObsceneStopwords)
+	if p.ObsceneWords != nil {
+		params["obscene_words"] = strings.Join(p.ObsceneWords, ",")
+	}
+
+	return g.vk.requestBool("groups.edit", params)
+}
+
+// Unban removes a user or a group from the community blacklist.
+// https://vk.com/dev/groups.unban
+func (g *Groups) Unban(groupId, ownerId int) (bool, error) {
+	params := map[string]string{
+		"group_id": strconv.Itoa(groupId),
+		"owner_id": strconv.Itoa(ownerId),
+	}
+
+	return g.vk.requestBool("groups.unban", params)
+}
+
+// GroupsGetBannedParams provides fields for GetBanned params.
+// https://vk.com/dev/groups.getBanned
+type GroupsGetBannedParams struct {
+	GroupID int
+	Offset  int
+	Count   int
+	Fields  UserFields
+	// If set, returns info only about this user or group.
+	OwnerID int
+}
+
+// A BanInfo describes why and until when a user is banned.
+// https://vk.com/dev/groups.getBanned
+type BanInfo struct {
+	AdminID        int       `json:"admin_id"`
+	Date           UnixTime  `json:"date"`
+	Reason         BanReason `json:"reason"`
+	Comment        string    `json:"comment"`
+	EndDate        UnixTime  `json:"end_date"`
+	CommentVisible bool      `json:"comment_visible"`
+}
+
+// GroupsGetBannedResponse describes the community blacklist.
+// https://vk.com/dev/groups.getBanned
+type GroupsGetBannedResponse struct {
+	Count int `json:"count"`
+	Items []struct {
+		// one of: profile, group
+		Type    string       `json:"type"`
+		Profile *UserObject  `json:"profile"`
+		Group   *GroupObject `json:"group"`
+		BanInfo BanInfo      `json:"ban_info"`
+	} `json:"items"`
+}
+
+// GetBanned returns a list of users and groups on the community blacklist.
+// https://vk.com/dev/groups.getBanned
+func (g *Groups) GetBanned(p GroupsGetBannedParams) (*GroupsGetBannedResponse, error) {
+	// set default count
+	count := 20
+	if p.Count != 0 {
+		count = p.Count
+	}
+	params := map[string]string{
+		"group_id": strconv.Itoa(p.GroupID),
+		"offset":   strconv.Itoa(p.Offset),
+		"count":    strconv.Itoa(count),
+		"fields":   p.Fields.String(),
+	}
+	if p.OwnerID != 0 {
+		params["owner_id"] = strconv.Itoa(p.OwnerID)
+	}
+	resp, err := g.vk.Request("groups.getBanned", params)
+	if err != nil {
+		return nil, err
+	}
+	res := &GroupsGetBannedResponse{}
+	err = json.Unmarshal(resp, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GroupsEditManagerParams provides fields for EditManager params.
+// https://vk.com/dev/groups.editManager
+type GroupsEditManagerParams struct {
+	GroupID int
+	UserID  int
+	// one of: moderator, editor, administrator.
+	// Empty to remove the user from managers.
+	Role            string
+	IsContact       bool
+	ContactPosition string
+	ContactPhone    string
+	ContactEmail    string
+}
+
+// EditManager allows to add, remove or edit the community manager.
+// https://vk.com/dev/groups.editManager
+func (g *Groups) EditManager(p GroupsEditManagerParams) (bool, error) {
+	params := map[string]string{
+		"group_id":         strconv.Itoa(p.GroupID),
+		"user_id":          strconv.Itoa(p.UserID),
+		"role":             p.Role,
+		"is_contact":       boolConverter(p.IsContact),
+		"contact_position": p.ContactPosition,
+		"contact_phone":    p.ContactPhone,
+		"contact_email":    p.ContactEmail,
+	}
+
+	return g.vk.requestBool("groups.editManager", params)
+}
+
+// ApproveRequest allows to approve join request to the community.
+// https://vk.com/dev/groups.approveRequest
+func (g *Groups) ApproveRequest(groupId, userId int) (bool, error) {
+	params := map[string]string{
+		"group_id": strconv.Itoa(groupId),
+		"user_id":  strconv.Itoa(userId),
+	}
+
+	return g.vk.requestBool("groups.approveRequest", params)
+}
+
+// GroupsGetRequestsResponse describes a list of requests to the community.
+// https://vk.com/dev/groups.getRequests
+type GroupsGetRequestsResponse struct {
+	Count int          `json:"count"`
+	Items []UserObject `json:"items"`
+}
+
+// GetRequests returns a list of requests to the community.
+// https://vk.com/dev/groups.getRequests
+func (g *Groups) GetRequests(groupId, offset, count int, fields UserFields) (*GroupsGetRequestsResponse, error) {
+	// set field for return user object not id
+	if len(fields) == 0 {
+		fields = UserFields{UserFieldPhoto50}
+	}
+	params := map[string]string{
+		"group_id": strconv.Itoa(groupId),
+		"offset":   strconv.Itoa(offset),
+		"fields":   fields.String(),
+	}
+	if count != 0 {
+		params["count"] = strconv.Itoa(count)
+	}
+	resp, err := g.vk.Request("groups.getRequests", params)
+	if err != nil {
+		return nil, err
+	}
+	res := &GroupsGetRequestsResponse{}
+	err = json.Unmarshal(resp, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GroupsGetInvitesResponse describes a list of invitations to join communities.
+// https://vk.com/dev/groups.getInvites
+type GroupsGetInvitesResponse struct {
+	Count    int           `json:"count"`
+	Items    []GroupObject `json:"items"`
+	Profiles []UserObject  `json:"profiles"`
+}
+
+// GetInvites returns a list of invitations to join communities and events.
+// https://vk.com/dev/groups.getInvites
+func (g *Groups) GetInvites(offset, count int) (*GroupsGetInvitesResponse, error) {
+	params := map[string]string{
+		"offset":   strconv.Itoa(offset),
+		"extended": "1",
+	}
+	if count != 0 {
+		params["count"] = strconv.Itoa(count)
+	}
+	resp, err := g.vk.Request("groups.getInvites", params)
+	if err != nil {
+		return nil, err
+	}
+	res := &GroupsGetInvitesResponse{}
+	err = json.Unmarshal(resp, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Invite allows to invite friends to the community.
+// https://vk.com/dev/groups.invite
+func (g *Groups) Invite(groupId, userId int) (bool, error) {
+	params := map[string]string{
+		"group_id": strconv.Itoa(groupId),
+		"user_id":  strconv.Itoa(userId),
+	}
+
+	return g.vk.requestBool("groups.invite", params)
+}
+
+// Join this method allows the current user to join a community.
+// notSure is used only for events.
+// https://vk.com/dev/groups.join
+func (g *Groups) Join(groupId int, notSure bool) (bool, error) {
+	params := map[string]string{
+		"group_id": strconv.Itoa(groupId),
+		"not_sure": boolConverter(notSure),
+	}
+
+	return g.vk.requestBool("groups.join", params)
+}
+
+// Leave makes the current user leave a community.
+// https://vk.com/dev/groups.leave
+func (g *Groups) Leave(groupId int) (bool, error) {
+	params := map[string]string{
+		"group_id": strconv.Itoa(groupId),
+	}
+
+	return g.vk.requestBool("groups.leave", params)
+}
+
+// RemoveUser removes a user from the community.
+// https://vk.com/dev/groups.removeUser
+func (g *Groups) RemoveUser(groupId, userId int) (bool, error) {
+	params := map[string]string{
+		"group_id": strconv.Itoa(groupId),
+		"user_id":  strconv.Itoa(userId),
+	}
+
+	return g.vk.requestBool("groups.removeUser", params)
+}
+
+// GroupsCreateParams provides fields for Create params.
+// https://vk.com/dev/groups.create
+type GroupsCreateParams struct {
+	Title       string
+	Description string
+	// one of: group (default), event, public
+	Type string
+	// category of a public page
+	PublicCategory int
+	// 1 — place or small business, 2 — company, organization or website,
+	// 3 — famous person or group of people, 4 — product or work of art
+	Subtype int
+}
+
+// Create creates a new community.
+// https://vk.com/dev/groups.create
+func (g *Groups) Create(p GroupsCreateParams) (*GroupObject, error) {
+	params := map[string]string{
+		"title":       p.Title,
+		"description": p.Description,
+		"type":        p.Type,
+	}
+	if p.PublicCategory != 0 {
+		params["public_category"] = strconv.Itoa(p.PublicCategory)
+	}
+	if p.Subtype != 0 {
+		params["subtype"] = strconv.Itoa(p.Subtype)
+	}
+	resp, err := g.vk.Request("groups.create", params)
+	if err != nil {
+		return nil, err
+	}
+	res := &GroupObject{}
+	err = json.Unmarshal(resp, res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetTokenPermissions returns permissions of the community token.
+// https://vk.com/dev/groups.getTokenPermissions
+func (g *Groups) GetTokenPermissions() (GroupScope, error) {
+	resp, err := g.vk.Request("groups.getTokenPermissions", nil)
+	if err != nil {
+		return 0, err
+	}
+	var res struct {
+		Mask uint `json:"mask"`
+	}
+	err = json.Unmarshal(resp, &res)
 	if err != nil {
-		return false, err
+		return 0, err
 	}
-	return ok == 1, nil
+	return GroupScope(res.Mask), nil
 }
@@ -2,6 +2,7 @@ package easyvk
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 )
 
@@ -11,36 +12,42 @@ type Likes struct {
 	vk *VK
 }
 
-type likeType string
+// ErrLikeItemNotFound is returned by IsLiked
+// when the object doesn't exist or is deleted.
+var ErrLikeItemNotFound = errors.New("likes: item not found")
+
+// LikeType is a type of the object which can be liked.
+// https://vk.com/dev/likes.add
+type LikeType string
 
 const (
 	// PostLikeType - post on user or community wall
-	PostLikeType likeType = "post"
+	PostLikeType LikeType = "post"
 	// CommentLikeType - comment on a wall post
-	CommentLikeType likeType = "comment"
+	CommentLikeType LikeType = "comment"
 	// PhotoLikeType - photo
-	PhotoLikeType likeType = "photo"
+	PhotoLikeType LikeType = "photo"
 	// AudioLikeType - audio
-	AudioLikeType likeType = "audio"
+	AudioLikeType LikeType = "audio"
 	// VideoLikeType - video
-	VideoLikeType likeType = "video"
+	VideoLikeType LikeType = "video"
 	// NoteLikeType - note
-	NoteLikeType likeType = "note"
+	NoteLikeType LikeType = "note"
 	// MarketLikeType - market
-	MarketLikeType likeType = "market"
+	MarketLikeType LikeType = "market"
 	// PhotoCommentLikeType - comment on the photo
-	PhotoCommentLikeType likeType = "photo_comment"
+	PhotoCommentLikeType LikeType = "photo_comment"
 	// VideoCommentLikeType - comment on the video
-	VideoCommentLikeType likeType = "video_comment"
+	VideoCommentLikeType LikeType = "video_comment"
 	// TopicCommentLikeType - comment in the discussion
-	TopicCommentLikeType likeType = "topic_comment"
+	TopicCommentLikeType LikeType = "topic_comment"
 	// MarketCommentLikeType - comment on the market
-	MarketCommentLikeType likeType = "market_comment"
+	MarketCommentLikeType LikeType = "market_comment"
 )
 
 // Add adds the specified object to the Likes list of the current user.
 // https://vk.com/dev/likes.add
-func (l *Likes) Add(t likeType, ownerID int, itemID uint, accessKey string) (int, error) {
+func (l *Likes) Add(t LikeType, ownerID int, itemID uint, accessKey string) (int, error) {
 	params := map[string]string{
 		"type":       string(t),
 		"owner_id":   fmt.Sprint(ownerID),
@@ -63,7 +70,7 @@ func (l *Likes) Add(t likeType, ownerID int, itemID uint, accessKey string) (int
 
 // Delete deletes the specified object from the Likes list of the current user.
 // https://vk.com/dev/likes.delete
-func (l *Likes) Delete(t likeType, ownerID int, itemID uint) (int, error) {
+func (l *Likes) Delete(t LikeType, ownerID int, itemID uint) (int, error) {
 	params := map[string]string{
 		"type":     string(t),
 		"owner_id": fmt.Sprint(ownerID),
@@ -92,7 +99,7 @@ type LikesIsLikedResponse struct {
 
 // IsLiked checks for the object in the Likes list of the specified user.
 // https://vk.com/dev/likes.isLiked
-func (l *Likes) IsLiked(userID uint, t likeType, ownerID int, itemID uint) (LikesIsLikedResponse, error) {
+func (l *Likes) IsLiked(userID uint, t LikeType, ownerID int, itemID uint) (LikesIsLikedResponse, error) {
 	params := map[string]string{
 		"type":     string(t),
 		"owner_id": fmt.Sprint(ownerID),
@@ -103,29 +110,45 @@ func (l *Likes) IsLiked(userID uint, t likeType, ownerID int, itemID uint) (Like
 	if err != nil {
 		return LikesIsLikedResponse{}, err
 	}
+	// VK answers without liked if there is no such object
 	var r struct {
-		Liked  int `json:"liked"`
-		Copied int `json:"copied"`
+		Liked  *int `json:"liked"`
+		Copied int  `json:"copied"`
 	}
 	err = json.Unmarshal(resp, &r)
 	if err != nil {
 		return LikesIsLikedResponse{}, err
 	}
+	if r.Liked == nil {
+		return LikesIsLikedResponse{}, ErrLikeItemNotFound
+	}
 	response := LikesIsLikedResponse{
-		r.Liked == 1,
+		*r.Liked == 1,
 		r.Copied == 1,
 	}
 	return response, nil
 }
 
+// LikesFilter selects users to return by GetList.
+// https://vk.com/dev/likes.getList
+type LikesFilter string
+
+const (
+	// LikesFilterLikes - users who liked the object
+	LikesFilterLikes LikesFilter = "likes"
+	// LikesFilterCopies - users who reposted the object
+	LikesFilterCopies LikesFilter = "copies"
+)
+
 // LikesGetListParams provides struct for getList parameters.
 // https://vk.com/dev/likes.getList
 type LikesGetListParams struct {
-	Type        likeType
-	OwnerID     int
-	ItemID      int
-	PageURL     string
-	Filter      string
+	Type    LikeType
+	OwnerID int
+	ItemID  int
+	PageURL string
+	// LikesFilterLikes (default) or LikesFilterCopies
+	Filter      LikesFilter
 	FriendsOnly bool
 	Offset      uint
 	Count       uint
@@ -139,29 +162,52 @@ type LikesGetListResponse struct {
 	Items []UserObject
 }
 
-// GetList returns a list of IDs of users who added the specified object to their Likes list.
+// LikesGetListIDsResponse describes a list of IDs of users.
+// https://vk.com/dev/likes.getList
+type LikesGetListIDsResponse struct {
+	Count int   `json:"count"`
+	Items []int `json:"items"`
+}
+
+// GetList returns a list of users who added the specified object to their Likes list.
 // https://vk.com/dev/likes.getList
 func (l *Likes) GetList(params LikesGetListParams) (LikesGetListResponse, error) {
+	var response LikesGetListResponse
+	err := l.getList(params, true, &response)
+	if err != nil {
+		return LikesGetListResponse{}, err
+	}
+	return response, nil
+}
+
+// GetListIDs returns a list of IDs of users who added the specified object to their Likes list.
+// It's lighter than GetList when profiles are not needed.
+// https://vk.com/dev/likes.getList
+func (l *Likes) GetListIDs(params LikesGetListParams) (LikesGetListIDsResponse, error) {
+	var response LikesGetListIDsResponse
+	err := l.getList(params, false, &response)
+	if err != nil {
+		return LikesGetListIDsResponse{}, err
+	}
+	return response, nil
+}
+
+func (l *Likes) getList(params LikesGetListParams, extended bool, v interface{}) error {
 	p := map[string]string{
 		"type":         string(params.Type),
 		"owner_id":     fmt.Sprint(params.OwnerID),
 		"item_id":      fmt.Sprint(params.ItemID),
 		"page_url":     params.PageURL,
-		"filter":       params.Filter,
+		"filter":       string(params.Filter),
 		"friends_only": boolConverter(params.FriendsOnly),
-		"extended":     "1",
+		"extended":     boolConverter(extended),
 		"offset":       fmt.Sprint(params.Offset),
 		"count":        fmt.Sprint(params.Count),
 		"skip_own":     boolConverter(params.SkipOwner),
 	}
 	resp, err := l.vk.Request("likes.getList", p)
 	if err != nil {
-		return LikesGetListResponse{}, err
+		return err
 	}
-	var response LikesGetListResponse
-	err = json.Unmarshal(resp, &response)
-	if err != nil {
-		return LikesGetListResponse{}, err
-	}
-	return response, nil
+	return json.Unmarshal(resp, v)
 }
@@ -0,0 +1,77 @@
+package easyvk
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"testing"
+)
+
+// likesServer answers every request with the response
+// and keeps the form of the last request.
+func likesServer(response string, form *url.Values) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		*form = r.Form
+		fmt.Fprintf(w, `{"response": %s}`, response)
+	}))
+}
+
+func TestLikesIsLiked(t *testing.T) {
+	tests := []struct {
+		response string
+		want     LikesIsLikedResponse
+		err      error
+	}{
+		{`{"liked": 1, "copied": 0}`, LikesIsLikedResponse{Liked: true}, nil},
+		{`{"liked": 0, "copied": 1}`, LikesIsLikedResponse{Copied: true}, nil},
+		{`{"copied": 0}`, LikesIsLikedResponse{}, ErrLikeItemNotFound},
+		{`{}`, LikesIsLikedResponse{}, ErrLikeItemNotFound},
+	}
+	for _, tt := range tests {
+		var form url.Values
+		srv := likesServer(tt.response, &form)
+		vk := WithToken("token")
+		vk.ApiUrl = srv.URL + "/"
+		got, err := vk.Likes.IsLiked(7, PostLikeType, -1, 5)
+		srv.Close()
+
+		if err != tt.err || got != tt.want {
+			t.Errorf("%s: got %+v, %v, want %+v, %v", tt.response, got, err, tt.want, tt.err)
+		}
+		if form.Get("user_id") != "7" || form.Get("type") != "post" || form.Get("owner_id") != "-1" || form.Get("item_id") != "5" {
+			t.Errorf("%s: got form %v", tt.response, form)
+		}
+	}
+}
+
+func TestLikesGetListIDs(t *testing.T) {
+	var form url.Values
+	srv := likesServer(`{"count": 3, "items": [1, 20, 300]}`, &form)
+	defer srv.Close()
+	vk := WithToken("token")
+	vk.ApiUrl = srv.URL + "/"
+
+	ids, err := vk.Likes.GetListIDs(LikesGetListParams{Type: PhotoLikeType, OwnerID: 1, ItemID: 2, Filter: LikesFilterCopies, Count: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if form.Get("extended") != "0" || form.Get("filter") != "copies" || form.Get("type") != "photo" || form.Get("count") != "3" {
+		t.Errorf("got form %v", form)
+	}
+	want := LikesGetListIDsResponse{Count: 3, Items: []int{1, 20, 300}}
+	if !reflect.DeepEqual(ids, want) {
+		t.Errorf("got %+v, want %+v", ids, want)
+	}
+
+	// GetList asks for profiles
+	_, err = vk.Likes.GetList(LikesGetListParams{Type: PhotoLikeType, OwnerID: 1, ItemID: 2})
+	if err == nil {
+		t.Error("ids are decoded as profiles")
+	}
+	if form.Get("extended") != "1" {
+		t.Errorf("got extended %q", form.Get("extended"))
+	}
+}
@@ -0,0 +1,127 @@
+package easyvk
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// A GroupLongPoll describes a client of Bots Long Poll API.
+// It receives the same events as CallbackHandler
+// without a public server.
+// https://vk.com/dev/bots_longpoll
+type GroupLongPoll struct {
+	vk      *VK
+	GroupID int
+	// Seconds to wait for events in one request, 25 by default, 90 max.
+	Wait int
+	// http.DefaultClient if nil, its timeout must be longer than Wait.
+	Client *http.Client
+
+	server *GroupsGetLongPollServerResponse
+}
+
+// NewGroupLongPoll returns a long poll client of the community.
+// Events must be enabled in the community's Long Poll API settings.
+func NewGroupLongPoll(vk *VK, groupID int) *GroupLongPoll {
+	return &GroupLongPoll{vk: vk, GroupID: groupID}
+}
+
+// Poll waits for new events and returns them.
+// It may return no events if the wait time is up.
+func (lp *GroupLongPoll) Poll() ([]CallbackEvent, error) {
+	if lp.server == nil {
+		err := lp.connect(true)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	wait := lp.Wait
+	if wait <= 0 {
+		wait = 25
+	}
+	query := url.Values{}
+	query.Set("act", "a_check")
+	query.Set("key", lp.server.Key)
+	query.Set("ts", lp.server.TS)
+	query.Set("wait", strconv.Itoa(wait))
+
+	client := lp.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Get(lp.server.Server + "?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("long poll: %s", resp.Status)
+	}
+
+	var result struct {
+		TS      json.RawMessage `json:"ts"`
+		Updates []CallbackEvent `json:"updates"`
+		Failed  int             `json:"failed"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return nil, err
+	}
+	// ts is a string in updates and a number in errors
+	ts := strings.Trim(string(result.TS), `"`)
+
+	switch result.Failed {
+	case 0:
+		lp.server.TS = ts
+		return result.Updates, nil
+	case 1:
+		// events are lost, continue from the given ts
+		lp.server.TS = ts
+		return nil, nil
+	case 2:
+		// the key is expired
+		return nil, lp.connect(false)
+	case 3:
+		// the key and ts are lost
+		return nil, lp.connect(true)
+	}
+	return nil, fmt.Errorf("long poll: failed %d", result.Failed)
+}
+
+// Run calls handle for every event until stop is closed
+// or a request fails.
+func (lp *GroupLongPoll) Run(stop <-chan struct{}, handle func(e CallbackEvent)) error {
+	for {
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+
+		events, err := lp.Poll()
+		if err != nil {
+			return err
+		}
+		for _, e := range events {
+			handle(e)
+		}
+	}
+}
+
+// connect gets a new key of the server, with a new ts if resetTS is set.
+func (lp *GroupLongPoll) connect(resetTS bool) error {
+	server, err := lp.vk.Groups.GetLongPollServer(lp.GroupID)
+	if err != nil {
+		return err
+	}
+	if !resetTS && lp.server != nil {
+		server.TS = lp.server.TS
+	}
+	lp.server = server
+	return nil
+}
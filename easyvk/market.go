@@ -1,10 +1,14 @@
 package easyvk
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 )
 
+// A Market describes a set of methods
+// to work with products.
 // https://vk.com/dev/market
 type Market struct {
 	vk *VK
@@ -28,3 +32,588 @@ func (m *Market) DeleteComment(ownerID, commentId int) (bool, error) {
 	}
 	return ok == 1, nil
 }
+
+// MarketCreateCommentParams provides fields for CreateComment params.
+// https://vk.com/dev/market.createComment
+type MarketCreateCommentParams struct {
+	OwnerID int
+	ItemID  int
+	Message string
+	// comma separated list like photo100172_166443618
+	Attachments    string
+	FromGroup      bool
+	ReplyToComment int
+	StickerID      int
+	GUID           string
+}
+
+// CreateComment adds a new comment on the product
+// and returns ID of the comment.
+// https://vk.com/dev/market.createComment
+func (m *Market) CreateComment(p MarketCreateCommentParams) (int, error) {
+	params := map[string]string{
+		"owner_id":    strconv.Itoa(p.OwnerID),
+		"item_id":     strconv.Itoa(p.ItemID),
+		"message":     p.Message,
+		"attachments": p.Attachments,
+		"from_group":  boolConverter(p.FromGroup),
+		"guid":        p.GUID,
+	}
+	if p.ReplyToComment != 0 {
+		params["reply_to_comment"] = strconv.Itoa(p.ReplyToComment)
+	}
+	if p.StickerID != 0 {
+		params["sticker_id"] = strconv.Itoa(p.StickerID)
+	}
+	resp, err := m.vk.Request("market.createComment", params)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(string(resp))
+}
+
+// MarketGetParams provides fields for Get params.
+// https://vk.com/dev/market.get
+type MarketGetParams struct {
+	OwnerID int
+	AlbumID int
+	Offset  int
+	Count   int
+	// Fill Photos, likes and so on.
+	Extended bool
+}
+
+// MarketGetResponse describes a list of products.
+// https://vk.com/dev/market.get
+type MarketGetResponse struct {
+	Count int                `json:"count"`
+	Items []MarketItemObject `json:"items"`
+}
+
+// Get returns products of the community.
+// https://vk.com/dev/market.get
+func (m *Market) Get(p MarketGetParams) (*MarketGetResponse, error) {
+	params := map[string]string{
+		"owner_id": strconv.Itoa(p.OwnerID),
+		"offset":   strconv.Itoa(p.Offset),
+		"extended": boolConverter(p.Extended),
+	}
+	if p.AlbumID != 0 {
+		params["album_id"] = strconv.Itoa(p.AlbumID)
+	}
+	if p.Count != 0 {
+		params["count"] = strconv.Itoa(p.Count)
+	}
+	return m.getList("market.get", params)
+}
+
+// GetById returns products by their IDs like "-4363_136089".
+// https://vk.com/dev/market.getById
+func (m *Market) GetById(itemIDs []string, extended bool) (*MarketGetResponse, error) {
+	params := map[string]string{
+		"item_ids": strings.Join(itemIDs, ","),
+		"extended": boolConverter(extended),
+	}
+	return m.getList("market.getById", params)
+}
+
+// MarketSearchParams provides fields for Search params.
+// https://vk.com/dev/market.search
+type MarketSearchParams struct {
+	OwnerID int
+	AlbumID int
+	Q       string
+	// Price limits in hundredths of the currency.
+	PriceFrom int
+	PriceTo   int
+	// 0 — as in the community, 1 — by date, 2 — by price, 3 — by popularity
+	Sort int
+	// Reverse order.
+	Rev      bool
+	Offset   int
+	Count    int
+	Extended bool
+}
+
+// Search searches products of the community.
+// https://vk.com/dev/market.search
+func (m *Market) Search(p MarketSearchParams) (*MarketGetResponse, error) {
+	params := map[string]string{
+		"owner_id": strconv.Itoa(p.OwnerID),
+		"q":        p.Q,
+		"sort":     strconv.Itoa(p.Sort),
+		"rev":      boolConverter(p.Rev),
+		"offset":   strconv.Itoa(p.Offset),
+		"extended": boolConverter(p.Extended),
+	}
+	if p.AlbumID != 0 {
+		params["album_id"] = strconv.Itoa(p.AlbumID)
+	}
+	if p.PriceFrom != 0 {
+		params["price_from"] = strconv.Itoa(p.PriceFrom)
+	}
+	if p.PriceTo != 0 {
+		params["price_to"] = strconv.Itoa(p.PriceTo)
+	}
+	if p.Count != 0 {
+		params["count"] = strconv.Itoa(p.Count)
+	}
+	return m.getList("market.search", params)
+}
+
+func (m *Market) getList(method string, params map[string]string) (*MarketGetResponse, error) {
+	resp, err := m.vk.Request(method, params)
+	if err != nil {
+		return nil, err
+	}
+	var items MarketGetResponse
+	err = json.Unmarshal(resp, &items)
+	if err != nil {
+		return nil, err
+	}
+	return &items, nil
+}
+
+// MarketAddParams provides fields for Add params.
+// https://vk.com/dev/market.add
+type MarketAddParams struct {
+	// Owner of the products, negative ID of the community.
+	OwnerID     int
+	Name        string
+	Description string
+	CategoryID  int
+	Price       float64
+	OldPrice    float64
+	Deleted     bool
+	// Photos uploaded with Photos.UploadMarketPhoto,
+	// the main one and up to 4 others.
+	MainPhotoID int
+	PhotoIDs    []int
+	URL         string
+	// Dimensions in millimeters and weight in grams.
+	DimensionWidth  int
+	DimensionHeight int
+	DimensionLength int
+	Weight          int
+	SKU             string
+}
+
+func (p MarketAddParams) params() map[string]string {
+	params := map[string]string{
+		"owner_id":      strconv.Itoa(p.OwnerID),
+		"name":          p.Name,
+		"description":   p.Description,
+		"category_id":   strconv.Itoa(p.CategoryID),
+		"price":         strconv.FormatFloat(p.Price, 'f', -1, 64),
+		"deleted":       boolConverter(p.Deleted),
+		"main_photo_id": strconv.Itoa(p.MainPhotoID),
+	}
+	if p.OldPrice != 0 {
+		params["old_price"] = strconv.FormatFloat(p.OldPrice, 'f', -1, 64)
+	}
+	if len(p.PhotoIDs) > 0 {
+		params["photo_ids"] = strings.Join(intIdsToString(p.PhotoIDs), ",")
+	}
+	if p.URL != "" {
+		params["url"] = p.URL
+	}
+	if p.DimensionWidth != 0 {
+		params["dimension_width"] = strconv.Itoa(p.DimensionWidth)
+	}
+	if p.DimensionHeight != 0 {
+		params["dimension_height"] = strconv.Itoa(p.DimensionHeight)
+	}
+	if p.DimensionLength != 0 {
+		params["dimension_length"] = strconv.Itoa(p.DimensionLength)
+	}
+	if p.Weight != 0 {
+		params["weight"] = strconv.Itoa(p.Weight)
+	}
+	if p.SKU != "" {
+		params["sku"] = p.SKU
+	}
+	return params
+}
+
+// Add adds a new product and returns its ID.
+// https://vk.com/dev/market.add
+func (m *Market) Add(p MarketAddParams) (int, error) {
+	resp, err := m.vk.Request("market.add", p.params())
+	if err != nil {
+		return 0, err
+	}
+	var item struct {
+		MarketItemID int `json:"market_item_id"`
+	}
+	err = json.Unmarshal(resp, &item)
+	if err != nil {
+		return 0, err
+	}
+	return item.MarketItemID, nil
+}
+
+// MarketEditParams provides fields for Edit params.
+// All fields of the product are replaced.
+// https://vk.com/dev/market.edit
+type MarketEditParams struct {
+	ItemID int
+	MarketAddParams
+}
+
+// Edit edits the product.
+// https://vk.com/dev/market.edit
+func (m *Market) Edit(p MarketEditParams) (bool, error) {
+	params := p.params()
+	params["item_id"] = strconv.Itoa(p.ItemID)
+	// zero values clear the old price and the link
+	params["old_price"] = strconv.FormatFloat(p.OldPrice, 'f', -1, 64)
+	params["url"] = p.URL
+	return m.vk.requestBool("market.edit", params)
+}
+
+// Delete deletes the product.
+// https://vk.com/dev/market.delete
+func (m *Market) Delete(ownerID, itemID int) (bool, error) {
+	params := map[string]string{
+		"owner_id": strconv.Itoa(ownerID),
+		"item_id":  strconv.Itoa(itemID),
+	}
+	return m.vk.requestBool("market.delete", params)
+}
+
+// Restore restores a recently deleted product.
+// https://vk.com/dev/market.restore
+func (m *Market) Restore(ownerID, itemID int) (bool, error) {
+	params := map[string]string{
+		"owner_id": strconv.Itoa(ownerID),
+		"item_id":  strconv.Itoa(itemID),
+	}
+	return m.vk.requestBool("market.restore", params)
+}
+
+// MarketReorderItemsParams provides fields for ReorderItems params.
+// https://vk.com/dev/market.reorderItems
+type MarketReorderItemsParams struct {
+	OwnerID int
+	// Collection to reorder, all products if zero.
+	AlbumID int
+	ItemID  int
+	// Product to place the item before or after, only one is set.
+	Before int
+	After  int
+}
+
+// ReorderItems moves the product before or after another product.
+// https://vk.com/dev/market.reorderItems
+func (m *Market) ReorderItems(p MarketReorderItemsParams) (bool, error) {
+	params := map[string]string{
+		"owner_id": strconv.Itoa(p.OwnerID),
+		"item_id":  strconv.Itoa(p.ItemID),
+	}
+	if p.AlbumID != 0 {
+		params["album_id"] = strconv.Itoa(p.AlbumID)
+	}
+	if p.Before != 0 {
+		params["before"] = strconv.Itoa(p.Before)
+	}
+	if p.After != 0 {
+		params["after"] = strconv.Itoa(p.After)
+	}
+	return m.vk.requestBool("market.reorderItems", params)
+}
+
+// MarketGetAlbumsResponse describes a list of collections.
+// https://vk.com/dev/market.getAlbums
+type MarketGetAlbumsResponse struct {
+	Count int                 `json:"count"`
+	Items []MarketAlbumObject `json:"items"`
+}
+
+// GetAlbums returns collections of products of the community.
+// https://vk.com/dev/market.getAlbums
+func (m *Market) GetAlbums(ownerID, offset, count int) (*MarketGetAlbumsResponse, error) {
+	params := map[string]string{
+		"owner_id": strconv.Itoa(ownerID),
+		"offset":   strconv.Itoa(offset),
+	}
+	if count != 0 {
+		params["count"] = strconv.Itoa(count)
+	}
+	resp, err := m.vk.Request("market.getAlbums", params)
+	if err != nil {
+		return nil, err
+	}
+	var albums MarketGetAlbumsResponse
+	err = json.Unmarshal(resp, &albums)
+	if err != nil {
+		return nil, err
+	}
+	return &albums, nil
+}
+
+// AddAlbum creates a collection of products and returns its ID.
+// photoID is the cover of the collection, may be zero.
+// https://vk.com/dev/market.addAlbum
+func (m *Market) AddAlbum(ownerID int, title string, photoID int, mainAlbum bool) (int, error) {
+	params := map[string]string{
+		"owner_id":   strconv.Itoa(ownerID),
+		"title":      title,
+		"main_album": boolConverter(mainAlbum),
+	}
+	if photoID != 0 {
+		params["photo_id"] = strconv.Itoa(photoID)
+	}
+	resp, err := m.vk.Request("market.addAlbum", params)
+	if err != nil {
+		return 0, err
+	}
+	var album struct {
+		MarketAlbumID int `json:"market_album_id"`
+	}
+	err = json.Unmarshal(resp, &album)
+	if err != nil {
+		return 0, err
+	}
+	return album.MarketAlbumID, nil
+}
+
+// MarketEditAlbumParams provides fields for EditAlbum params.
+// https://vk.com/dev/market.editAlbum
+type MarketEditAlbumParams struct {
+	OwnerID int
+	AlbumID int
+	Title   string
+	// Cover of the collection, may be zero.
+	PhotoID   int
+	MainAlbum bool
+}
+
+// EditAlbum edits the collection of products.
+// https://vk.com/dev/market.editAlbum
+func (m *Market) EditAlbum(p MarketEditAlbumParams) (bool, error) {
+	params := map[string]string{
+		"owner_id":   strconv.Itoa(p.OwnerID),
+		"album_id":   strconv.Itoa(p.AlbumID),
+		"title":      p.Title,
+		"main_album": boolConverter(p.MainAlbum),
+	}
+	if p.PhotoID != 0 {
+		params["photo_id"] = strconv.Itoa(p.PhotoID)
+	}
+	return m.vk.requestBool("market.editAlbum", params)
+}
+
+// AddToAlbum adds the product to the collections.
+// https://vk.com/dev/market.addToAlbum
+func (m *Market) AddToAlbum(ownerID, itemID int, albumIDs []int) (bool, error) {
+	params := map[string]string{
+		"owner_id":  strconv.Itoa(ownerID),
+		"item_id":   strconv.Itoa(itemID),
+		"album_ids": strings.Join(intIdsToString(albumIDs), ","),
+	}
+	return m.vk.requestBool("market.addToAlbum", params)
+}
+
+// MarketGetCategoriesResponse describes a list of categories.
+// https://vk.com/dev/market.getCategories
+type MarketGetCategoriesResponse struct {
+	Count int              `json:"count"`
+	Items []MarketCategory `json:"items"`
+}
+
+// GetCategories returns a list of product categories.
+// https://vk.com/dev/market.getCategories
+func (m *Market) GetCategories(offset, count int) (*MarketGetCategoriesResponse, error) {
+	params := map[string]string{
+		"offset": strconv.Itoa(offset),
+	}
+	if count != 0 {
+		params["count"] = strconv.Itoa(count)
+	}
+	resp, err := m.vk.Request("market.getCategories", params)
+	if err != nil {
+		return nil, err
+	}
+	var categories MarketGetCategoriesResponse
+	err = json.Unmarshal(resp, &categories)
+	if err != nil {
+		return nil, err
+	}
+	return &categories, nil
+}
+
+// MarketGetOrdersResponse describes a list of orders.
+// https://vk.com/dev/market.getOrders
+type MarketGetOrdersResponse struct {
+	Count int                 `json:"count"`
+	Items []MarketOrderObject `json:"items"`
+}
+
+// GetOrders returns orders of the current user.
+// https://vk.com/dev/market.getOrders
+func (m *Market) GetOrders(offset, count int, extended bool) (*MarketGetOrdersResponse, error) {
+	params := map[string]string{
+		"offset":   strconv.Itoa(offset),
+		"extended": boolConverter(extended),
+	}
+	if count != 0 {
+		params["count"] = strconv.Itoa(count)
+	}
+	return m.getOrders("market.getOrders", params)
+}
+
+// GetGroupOrders returns orders placed in the community.
+// https://vk.com/dev/market.getGroupOrders
+func (m *Market) GetGroupOrders(groupID, offset, count int) (*MarketGetOrdersResponse, error) {
+	params := map[string]string{
+		"group_id": strconv.Itoa(groupID),
+		"offset":   strconv.Itoa(offset),
+	}
+	if count != 0 {
+		params["count"] = strconv.Itoa(count)
+	}
+	return m.getOrders("market.getGroupOrders", params)
+}
+
+func (m *Market) getOrders(method string, params map[string]string) (*MarketGetOrdersResponse, error) {
+	resp, err := m.vk.Request(method, params)
+	if err != nil {
+		return nil, err
+	}
+	var orders MarketGetOrdersResponse
+	err = json.Unmarshal(resp, &orders)
+	if err != nil {
+		return nil, err
+	}
+	return &orders, nil
+}
+
+// GetOrderById returns the order of the user.
+// https://vk.com/dev/market.getOrderById
+func (m *Market) GetOrderById(userID, orderID int, extended bool) (*MarketOrderObject, error) {
+	params := map[string]string{
+		"order_id": strconv.Itoa(orderID),
+		"extended": boolConverter(extended),
+	}
+	if userID != 0 {
+		params["user_id"] = strconv.Itoa(userID)
+	}
+	resp, err := m.vk.Request("market.getOrderById", params)
+	if err != nil {
+		return nil, err
+	}
+	var order struct {
+		Order MarketOrderObject `json:"order"`
+	}
+	err = json.Unmarshal(resp, &order)
+	if err != nil {
+		return nil, err
+	}
+	return &order.Order, nil
+}
+
+// MarketEditOrderParams provides fields for EditOrder params.
+// Only non-nil fields are changed.
+// https://vk.com/dev/market.editOrder
+type MarketEditOrderParams struct {
+	UserID          int
+	OrderID         int
+	MerchantComment *string
+	// see MarketOrderObject.Status
+	Status      *int
+	TrackNumber *string
+	// one of: not_paid, paid, returned
+	PaymentStatus *string
+	// in hundredths of the currency
+	DeliveryPrice *int
+	// Dimensions in millimeters and weight in grams.
+	Width  *int
+	Length *int
+	Height *int
+	Weight *int
+}
+
+// EditOrder edits the order.
+// https://vk.com/dev/market.editOrder
+func (m *Market) EditOrder(p MarketEditOrderParams) (bool, error) {
+	params := map[string]string{
+		"user_id":  strconv.Itoa(p.UserID),
+		"order_id": strconv.Itoa(p.OrderID),
+	}
+	setString := func(name string, v *string) {
+		if v != nil {
+			params[name] = *v
+		}
+	}
+	setInt := func(name string, v *int) {
+		if v != nil {
+			params[name] = strconv.Itoa(*v)
+		}
+	}
+	setString("merchant_comment", p.MerchantComment)
+	setInt("status", p.Status)
+	setString("track_number", p.TrackNumber)
+	setString("payment_status", p.PaymentStatus)
+	setInt("delivery_price", p.DeliveryPrice)
+	setInt("width", p.Width)
+	setInt("length", p.Length)
+	setInt("height", p.Height)
+	setInt("weight", p.Weight)
+	return m.vk.requestBool("market.editOrder", params)
+}
+
+// MarketGetCommentsParams provides fields for GetComments params.
+// https://vk.com/dev/market.getComments
+type MarketGetCommentsParams struct {
+	OwnerID   int
+	ItemID    int
+	NeedLikes bool
+	// Comment to start from, Offset is counted from it.
+	StartCommentID int
+	Offset         int
+	Count          int
+	// one of: asc (default), desc
+	Sort string
+	// Fill Profiles and Groups of comment authors.
+	Extended bool
+	Fields   UserFields
+}
+
+// MarketGetCommentsResponse describes a list of comments on the product.
+// https://vk.com/dev/market.getComments
+type MarketGetCommentsResponse struct {
+	Count    int             `json:"count"`
+	Items    []CommentObject `json:"items"`
+	Profiles []UserObject    `json:"profiles"`
+	Groups   []GroupObject   `json:"groups"`
+}
+
+// GetComments returns comments on the product.
+// https://vk.com/dev/market.getComments
+func (m *Market) GetComments(p MarketGetCommentsParams) (*MarketGetCommentsResponse, error) {
+	params := map[string]string{
+		"owner_id":   strconv.Itoa(p.OwnerID),
+		"item_id":    strconv.Itoa(p.ItemID),
+		"need_likes": boolConverter(p.NeedLikes),
+		"offset":     strconv.Itoa(p.Offset),
+		"sort":       p.Sort,
+		"extended":   boolConverter(p.Extended),
+		"fields":     p.Fields.String(),
+	}
+	if p.StartCommentID != 0 {
+		params["start_comment_id"] = strconv.Itoa(p.StartCommentID)
+	}
+	if p.Count != 0 {
+		params["count"] = strconv.Itoa(p.Count)
+	}
+	resp, err := m.vk.Request("market.getComments", params)
+	if err != nil {
+		return nil, err
+	}
+	var comments MarketGetCommentsResponse
+	err = json.Unmarshal(resp, &comments)
+	if err != nil {
+		return nil, err
+	}
+	return &comments, nil
+}
@@ -0,0 +1,220 @@
+package easyvk
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+const (
+	// groups.getMembers returns at most 1000 members per call
+	membersPerCall = 1000
+	// and execute allows 25 calls per request
+	membersPerExecute = 25 * membersPerCall
+)
+
+const membersExecuteCode = `var offset = %d;
+var end = offset + %d;
+var count = end;
+var items = [];
+while (offset < end && offset < count) {
+	var r = API.groups.getMembers({"group_id": %d, "sort": %q, "filter": %q, "fields": %q, "offset": offset, "count": %d});
+	if (!r) {
+		return {"count": count, "items": items};
+	}
+	count = r.count;
+	items = items + r.items;
+	offset = offset + %d;
+}
+return {"count": count, "items": items};`
+
+// AllMembersParams provides fields for AllMembers params.
+// https://vk.com/dev/groups.getMembers
+type AllMembersParams struct {
+	GroupId int
+	// one of: id_asc (default), id_desc, time_asc, time_desc
+	Sort string
+	// one of: friends, unsure, managers, donut
+	Filter string
+	// If set, every member comes with filled User.
+	Fields UserFields
+	// Position to start from, e.g. saved Offset+1 of the last received member.
+	Offset int
+	// Number of parallel execute requests, 3 by default.
+	Workers int
+	// Limit of execute requests per second, 3 by default.
+	RequestsPerSecond int
+}
+
+// A MemberItem describes a single member
+// of the community streamed by AllMembers.
+type MemberItem struct {
+	ID int
+	// User is filled if AllMembersParams.Fields is set
+	// or if Filter is "managers".
+	User *UserObject
+	// Position of the member in the list.
+	Offset int
+	// Err is set on the last item if the export has failed.
+	// To resume the export pass Offset as AllMembersParams.Offset.
+	Err error
+}
+
+type membersChunk struct {
+	Count int               `json:"count"`
+	Items []json.RawMessage `json:"items"`
+	// err is set if the chunk has stopped on a failed call,
+	// Items hold members got before it.
+	err error
+}
+
+type membersJob struct {
+	offset int
+	result chan membersJobResult
+}
+
+type membersJobResult struct {
+	chunk *membersChunk
+	err   error
+}
+
+// AllMembers streams all members of the community.
+// It gets up to 25000 members per execute request and runs
+// several requests in parallel, members still come in order.
+// The channel is closed when all members have been sent
+// or after the item with an error. Close stop to end
+// the export early if you don't read the channel till the end.
+// https://vk.com/dev/groups.getMembers
+func (g *Groups) AllMembers(p AllMembersParams, stop <-chan struct{}) <-chan MemberItem {
+	out := make(chan MemberItem, membersPerCall)
+	go g.allMembers(p, out, stop)
+	return out
+}
+
+func (g *Groups) allMembers(p AllMembersParams, out chan<- MemberItem, stop <-chan struct{}) {
+	defer close(out)
+
+	workers := 3
+	if p.Workers > 0 {
+		workers = p.Workers
+	}
+	rps := 3
+	if p.RequestsPerSecond > 0 {
+		rps = p.RequestsPerSecond
+	}
+	limiter := newRateLimiter(rps)
+
+	// first chunk tells us how many members there are
+	limiter.Wait()
+	first, err := g.membersChunk(p, p.Offset)
+	if err != nil {
+		sendMember(out, MemberItem{Offset: p.Offset, Err: err}, stop)
+		return
+	}
+	if !emitMembers(out, first, p.Offset, stop) {
+		return
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+
+	pending := make(chan membersJob, workers)
+	sem := make(chan struct{}, workers)
+	go func() {
+		defer close(pending)
+		for offset := p.Offset + membersPerExecute; offset < first.Count; offset += membersPerExecute {
+			select {
+			case sem <- struct{}{}:
+			case <-done:
+				return
+			case <-stop:
+				return
+			}
+			job := membersJob{offset, make(chan membersJobResult, 1)}
+			go func() {
+				defer func() { <-sem }()
+				limiter.Wait()
+				chunk, err := g.membersChunk(p, job.offset)
+				job.result <- membersJobResult{chunk, err}
+			}()
+			select {
+			case pending <- job:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	for job := range pending {
+		res := <-job.result
+		if res.err != nil {
+			sendMember(out, MemberItem{Offset: job.offset, Err: res.err}, stop)
+			return
+		}
+		if !emitMembers(out, res.chunk, job.offset, stop) {
+			return
+		}
+	}
+}
+
+func (g *Groups) membersChunk(p AllMembersParams, offset int) (*membersChunk, error) {
+	code := fmt.Sprintf(membersExecuteCode,
+		offset, membersPerExecute,
+		p.GroupId, p.Sort, p.Filter, p.Fields.String(),
+		membersPerCall, membersPerCall,
+	)
+	resp, err := g.vk.Execute(code)
+	// the code stops on a failed call and returns members got before it
+	execErr, failed := err.(*ExecuteError)
+	if failed {
+		resp = execErr.Response
+	} else if err != nil {
+		return nil, err
+	}
+	chunk := &membersChunk{}
+	err = json.Unmarshal(resp, chunk)
+	if err != nil {
+		return nil, err
+	}
+	if failed {
+		chunk.err = execErr
+	}
+	return chunk, nil
+}
+
+// sendMember sends the item to out unless stop is closed.
+func sendMember(out chan<- MemberItem, item MemberItem, stop <-chan struct{}) bool {
+	select {
+	case out <- item:
+		return true
+	case <-stop:
+		return false
+	}
+}
+
+// emitMembers sends members of the chunk to out and then
+// the error of the chunk if it has stopped on a failed call.
+// Returns false if the chunk can't be decoded,
+// has failed or stop is closed.
+func emitMembers(out chan<- MemberItem, chunk *membersChunk, offset int, stop <-chan struct{}) bool {
+	for i, raw := range chunk.Items {
+		item := MemberItem{Offset: offset + i}
+		// with fields or managers filter items are objects, ids otherwise
+		if bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{")) {
+			user := &UserObject{}
+			item.Err = json.Unmarshal(raw, user)
+			item.ID = user.ID
+			item.User = user
+		} else {
+			item.Err = json.Unmarshal(raw, &item.ID)
+		}
+		if !sendMember(out, item, stop) || item.Err != nil {
+			return false
+		}
+	}
+	if chunk.err != nil {
+		sendMember(out, MemberItem{Offset: offset + len(chunk.Items), Err: chunk.err}, stop)
+		return false
+	}
+	return true
+}
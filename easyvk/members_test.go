@@ -0,0 +1,193 @@
+package easyvk
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEmitMembers(t *testing.T) {
+	tests := []struct {
+		name  string
+		items string
+		ids   []int
+		users []bool
+		ok    bool
+	}{
+		{"ids", `[1, 2, 3]`, []int{1, 2, 3}, []bool{false, false, false}, true},
+		{"objects", `[{"id": 5, "first_name": "A"}, {"id": 6}]`, []int{5, 6}, []bool{true, true}, true},
+		{"bad item", `[1, "x", 3]`, []int{1, 0}, []bool{false, false}, false},
+	}
+	for _, tt := range tests {
+		chunk := &membersChunk{}
+		err := json.Unmarshal([]byte(tt.items), &chunk.Items)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := make(chan MemberItem, len(chunk.Items))
+		ok := emitMembers(out, chunk, 100, nil)
+		close(out)
+
+		var ids []int
+		var users []bool
+		i := 0
+		for item := range out {
+			if item.Offset != 100+i {
+				t.Errorf("%s: item %d has offset %d", tt.name, i, item.Offset)
+			}
+			ids = append(ids, item.ID)
+			users = append(users, item.User != nil)
+			i++
+		}
+		if ok != tt.ok {
+			t.Errorf("%s: got %v, want %v", tt.name, ok, tt.ok)
+		}
+		if !reflect.DeepEqual(ids, tt.ids) || !reflect.DeepEqual(users, tt.users) {
+			t.Errorf("%s: got ids %v users %v, want %v %v", tt.name, ids, users, tt.ids, tt.users)
+		}
+	}
+}
+
+// membersServer answers execute requests of AllMembers
+// with ids from 1 to count. If failAt is positive, the call
+// which gets the member at failAt fails.
+func membersServer(t *testing.T, count, failAt int) (*httptest.Server, *[]int) {
+	var mu sync.Mutex
+	var offsets []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var offset, size int
+		_, err := fmt.Sscanf(r.FormValue("code"), "var offset = %d;\nvar end = offset + %d;", &offset, &size)
+		if err != nil {
+			t.Errorf("unexpected code: %v", err)
+		}
+		mu.Lock()
+		offsets = append(offsets, offset)
+		mu.Unlock()
+
+		items := []int{}
+		for id := offset + 1; id <= offset+size && id <= count; id++ {
+			if id == failAt {
+				// the code returns members got before the failed call
+				items = items[:len(items)/membersPerCall*membersPerCall]
+				json.NewEncoder(w).Encode(map[string]interface{}{
+					"response": map[string]interface{}{"count": count, "items": items},
+					"execute_errors": []map[string]interface{}{
+						{"method": "groups.getMembers", "error_code": 10, "error_msg": "Internal server error"},
+					},
+				})
+				return
+			}
+			items = append(items, id)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"response": map[string]interface{}{"count": count, "items": items},
+		})
+	}))
+	return srv, &offsets
+}
+
+func TestAllMembers(t *testing.T) {
+	tests := []struct {
+		count, offset int
+		calls         int
+	}{
+		{0, 0, 1},
+		{10, 0, 1},
+		{membersPerExecute, 0, 1},
+		{membersPerExecute + 1, 0, 2},
+		{3*membersPerExecute + 5, 0, 4},
+		{3*membersPerExecute + 5, membersPerExecute + 7, 2},
+	}
+	for _, tt := range tests {
+		srv, offsets := membersServer(t, tt.count, 0)
+		vk := WithToken("token")
+		vk.ApiUrl = srv.URL + "/"
+
+		next := tt.offset + 1
+		for item := range vk.Groups.AllMembers(AllMembersParams{GroupId: 1, Offset: tt.offset, RequestsPerSecond: 100}, nil) {
+			if item.Err != nil {
+				t.Fatalf("%d from %d: %v", tt.count, tt.offset, item.Err)
+			}
+			if item.ID != next || item.Offset != next-1 {
+				t.Fatalf("%d from %d: got member %d at %d, want %d", tt.count, tt.offset, item.ID, item.Offset, next)
+			}
+			next++
+		}
+		srv.Close()
+
+		if next != tt.count+1 && tt.count > tt.offset {
+			t.Errorf("%d from %d: stopped at %d", tt.count, tt.offset, next)
+		}
+		if len(*offsets) != tt.calls {
+			t.Errorf("%d from %d: got %d execute calls, want %d", tt.count, tt.offset, len(*offsets), tt.calls)
+		}
+	}
+}
+
+func TestAllMembersFailedCall(t *testing.T) {
+	tests := []struct {
+		count, failAt int
+	}{
+		{10, 1},
+		{5000, 3500},
+		{3 * membersPerExecute, membersPerExecute + 2*membersPerCall + 1},
+	}
+	for _, tt := range tests {
+		srv, _ := membersServer(t, tt.count, tt.failAt)
+		vk := WithToken("token")
+		vk.ApiUrl = srv.URL + "/"
+
+		var last MemberItem
+		n := 0
+		for item := range vk.Groups.AllMembers(AllMembersParams{GroupId: 1, RequestsPerSecond: 100}, nil) {
+			last = item
+			n++
+		}
+		srv.Close()
+
+		// members of the failed call are not sent,
+		// the error comes with the offset to resume from
+		want := (tt.failAt - 1) / membersPerCall * membersPerCall
+		if _, ok := last.Err.(*ExecuteError); !ok {
+			t.Errorf("fail at %d: got %v, want execute error", tt.failAt, last.Err)
+		}
+		if last.Offset != want || n != want+1 {
+			t.Errorf("fail at %d: got error at %d after %d items, want at %d", tt.failAt, last.Offset, n-1, want)
+		}
+	}
+}
+
+func TestAllMembersStop(t *testing.T) {
+	srv, _ := membersServer(t, 10*membersPerExecute, 0)
+	defer srv.Close()
+	vk := WithToken("token")
+	vk.ApiUrl = srv.URL + "/"
+
+	stop := make(chan struct{})
+	members := vk.Groups.AllMembers(AllMembersParams{GroupId: 1, Workers: 1, RequestsPerSecond: 100}, stop)
+	<-members
+	close(stop)
+
+	// the channel is closed soon after stop
+	done := make(chan int)
+	go func() {
+		n := 1
+		for range members {
+			n++
+		}
+		done <- n
+	}()
+	select {
+	case n := <-done:
+		if n == 10*membersPerExecute {
+			t.Errorf("got all %d members after stop", n)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("export is not stopped")
+	}
+}
@@ -0,0 +1,415 @@
+package easyvk
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// A MembersSnapshot describes a list of
+// community members at the given time.
+type MembersSnapshot struct {
+	GroupId int       `json:"group_id"`
+	Time    time.Time `json:"time"`
+	Members []int     `json:"members"`
+}
+
+// A SnapshotStore describes a storage of the last members
+// snapshot and of callback events per community.
+type SnapshotStore interface {
+	// Load returns the last saved snapshot
+	// or nil if there is no one yet.
+	Load(groupId int) (*MembersSnapshot, error)
+	Save(s *MembersSnapshot) error
+	// AppendEvent adds the change to the event log of the community.
+	AppendEvent(change MembershipChange) error
+	// Events returns changes from the log
+	// that happened not later than until.
+	Events(groupId int, until time.Time) ([]MembershipChange, error)
+	// DropEvents removes changes that happened
+	// not later than until from the log.
+	DropEvents(groupId int, until time.Time) error
+}
+
+// A FileSnapshotStore keeps snapshots as json files in the directory
+// and callback events as a log next to them, one json object per line.
+// The log is locked while it's changed, so several processes
+// can share the directory.
+type FileSnapshotStore struct {
+	Dir string
+}
+
+// A lock file older than this is left by a crashed process.
+const staleLockAge = 30 * time.Second
+
+func (s *FileSnapshotStore) path(groupId int) string {
+	return filepath.Join(s.Dir, fmt.Sprintf("members_%d.json", groupId))
+}
+
+func (s *FileSnapshotStore) eventsPath(groupId int) string {
+	return filepath.Join(s.Dir, fmt.Sprintf("members_%d.events", groupId))
+}
+
+// Load reads the snapshot of the community from the file.
+func (s *FileSnapshotStore) Load(groupId int) (*MembersSnapshot, error) {
+	data, err := ioutil.ReadFile(s.path(groupId))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &MembersSnapshot{}
+	err = json.Unmarshal(data, snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot, nil
+}
+
+// Save writes the snapshot to the file.
+func (s *FileSnapshotStore) Save(snapshot *MembersSnapshot) error {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(s.Dir, 0755)
+	if err != nil {
+		return err
+	}
+	// write to the temp file first, so a crash can't leave half of the snapshot
+	tmp := s.path(snapshot.GroupId) + ".tmp"
+	err = ioutil.WriteFile(tmp, data, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path(snapshot.GroupId))
+}
+
+// AppendEvent appends the change to the log file.
+func (s *FileSnapshotStore) AppendEvent(change MembershipChange) error {
+	line, err := json.Marshal(change)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(s.Dir, 0755)
+	if err != nil {
+		return err
+	}
+	path := s.eventsPath(change.GroupId)
+	unlock, err := lockFile(path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	if err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Events reads the log file.
+func (s *FileSnapshotStore) Events(groupId int, until time.Time) ([]MembershipChange, error) {
+	path := s.eventsPath(groupId)
+	if _, err := os.Stat(s.Dir); os.IsNotExist(err) {
+		return nil, nil
+	}
+	unlock, err := lockFile(path + ".lock")
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	events, _, err := s.readEvents(path, until)
+	return events, err
+}
+
+// DropEvents rewrites the log file with the changes after until.
+func (s *FileSnapshotStore) DropEvents(groupId int, until time.Time) error {
+	path := s.eventsPath(groupId)
+	if _, err := os.Stat(s.Dir); os.IsNotExist(err) {
+		return nil
+	}
+	unlock, err := lockFile(path + ".lock")
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	events, rest, err := s.readEvents(path, until)
+	if err != nil || len(events) == 0 {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	err = ioutil.WriteFile(tmp, rest, 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// readEvents returns changes of the log file that happened
+// not later than until and lines of the later ones.
+func (s *FileSnapshotStore) readEvents(path string, until time.Time) ([]MembershipChange, []byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var events []MembershipChange
+	var rest bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var change MembershipChange
+		err = json.Unmarshal(line, &change)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %v", path, err)
+		}
+		if change.Time.After(until) {
+			rest.Write(line)
+			rest.WriteByte('\n')
+		} else {
+			events = append(events, change)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return events, rest.Bytes(), nil
+}
+
+// lockFile creates the lock file, waiting while another process holds it,
+// and returns the function that removes it.
+func lockFile(path string) (func(), error) {
+	deadline := time.Now().Add(2 * staleLockAge)
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(path) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, err
+		}
+		info, err := os.Stat(path)
+		if err == nil && time.Since(info.ModTime()) > staleLockAge {
+			os.Remove(path)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("easyvk: %s is locked", path)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// MembershipChangeType is a type of the membership change.
+type MembershipChangeType string
+
+const (
+	// MemberJoined - user joined the community
+	MemberJoined MembershipChangeType = "join"
+	// MemberLeft - user left the community
+	MemberLeft MembershipChangeType = "leave"
+)
+
+// A MembershipChange describes a user
+// who joined or left the community.
+type MembershipChange struct {
+	GroupId int                  `json:"group_id"`
+	UserId  int                  `json:"user_id"`
+	Type    MembershipChangeType `json:"type"`
+	Time    time.Time            `json:"time"`
+	// FromCallback is true if the change was received as
+	// a callback event, Time is the time of the event then.
+	// Otherwise Time is the time of the snapshot.
+	FromCallback bool `json:"from_callback"`
+}
+
+// A MembersTracker describes a tracker that
+// finds who joined and left the community between runs.
+type MembersTracker struct {
+	vk    *VK
+	Store SnapshotStore
+}
+
+// NewMembersTracker returns a tracker that keeps snapshots in the store.
+// If store is nil, snapshots are kept as files in the current directory.
+func NewMembersTracker(vk *VK, store SnapshotStore) *MembersTracker {
+	if store == nil {
+		store = &FileSnapshotStore{Dir: "."}
+	}
+	return &MembersTracker{
+		vk:    vk,
+		Store: store,
+	}
+}
+
+// HandleCallbackEvent appends group_join and group_leave events to the log
+// of the store to give exact time to the changes found by Track, even if
+// Track runs in another process. Other events are ignored,
+// so it can be called for every event.
+func (t *MembersTracker) HandleCallbackEvent(e CallbackEvent) error {
+	var change MembershipChange
+	switch e.Type {
+	case GroupJoinEvent:
+		change.Type = MemberJoined
+	case GroupLeaveEvent:
+		change.Type = MemberLeft
+	default:
+		return nil
+	}
+
+	var obj struct {
+		UserId int `json:"user_id"`
+	}
+	err := json.Unmarshal(e.Object, &obj)
+	if err != nil {
+		return err
+	}
+	change.GroupId = e.GroupID
+	change.UserId = obj.UserId
+	change.Time = time.Now()
+	change.FromCallback = true
+	return t.Store.AppendEvent(change)
+}
+
+// Track takes a new snapshot of the community members and
+// returns changes since the previous one, sorted by time.
+// The first run for the community only saves a snapshot.
+// If the snapshot is saved but the events can't be dropped
+// from the log, changes are returned with the error,
+// the next run skips these events.
+func (t *MembersTracker) Track(groupId int) ([]MembershipChange, error) {
+	prev, err := t.Store.Load(groupId)
+	if err != nil {
+		return nil, err
+	}
+
+	cur := &MembersSnapshot{GroupId: groupId, Time: time.Now()}
+	for item := range t.vk.Groups.AllMembers(AllMembersParams{GroupId: groupId}, nil) {
+		if item.Err != nil {
+			return nil, item.Err
+		}
+		cur.Members = append(cur.Members, item.ID)
+	}
+	sort.Ints(cur.Members)
+
+	// events that came while members were fetched stay in the log
+	logged, err := t.Store.Events(groupId, cur.Time)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []MembershipChange
+	if prev != nil {
+		var events []MembershipChange
+		for _, e := range logged {
+			// events left by a failed drop are already reported
+			if e.Time.After(prev.Time) {
+				events = append(events, e)
+			}
+		}
+		changes = reconcile(prev, cur, events)
+	}
+
+	err = t.Store.Save(cur)
+	if err != nil {
+		return nil, err
+	}
+	// the events are dropped only when the snapshot
+	// which has taken them into account is saved
+	err = t.Store.DropEvents(groupId, cur.Time)
+	return changes, err
+}
+
+// reconcile finds changes between snapshots and replaces them
+// by callback events received between the snapshots.
+func reconcile(prev, cur *MembersSnapshot, events []MembershipChange) []MembershipChange {
+	events = append([]MembershipChange(nil), events...)
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Time.Before(events[j].Time)
+	})
+	byUser := map[int][]MembershipChange{}
+	for _, e := range events {
+		userEvents := byUser[e.UserId]
+		// VK sends the event again if the answer was late
+		if len(userEvents) > 0 && userEvents[len(userEvents)-1].Type == e.Type {
+			continue
+		}
+		byUser[e.UserId] = append(userEvents, e)
+	}
+
+	var changes []MembershipChange
+	add := func(userId int, typ MembershipChangeType) {
+		userEvents := byUser[userId]
+		delete(byUser, userId)
+		if len(userEvents) > 0 && userEvents[len(userEvents)-1].Type == typ {
+			changes = append(changes, userEvents...)
+			return
+		}
+		changes = append(changes, MembershipChange{
+			GroupId: cur.GroupId,
+			UserId:  userId,
+			Type:    typ,
+			Time:    cur.Time,
+		})
+	}
+
+	was := map[int]bool{}
+	for _, id := range prev.Members {
+		was[id] = true
+	}
+	is := map[int]bool{}
+	for _, id := range cur.Members {
+		is[id] = true
+		if !was[id] {
+			add(id, MemberJoined)
+		}
+	}
+	for _, id := range prev.Members {
+		if !is[id] {
+			add(id, MemberLeft)
+		}
+	}
+
+	// users who joined and left (or left and came back) between
+	// snapshots are visible only through callback events
+	for _, userEvents := range byUser {
+		joins := 0
+		for _, e := range userEvents {
+			if e.Type == MemberJoined {
+				joins++
+			}
+		}
+		if joins*2 == len(userEvents) {
+			changes = append(changes, userEvents...)
+		}
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Time.Before(changes[j].Time)
+	})
+	return changes
+}
@@ -0,0 +1,187 @@
+package easyvk
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFileSnapshotStoreEvents(t *testing.T) {
+	store := &FileSnapshotStore{Dir: t.TempDir()}
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			err := store.AppendEvent(MembershipChange{
+				GroupId: 1,
+				UserId:  i,
+				Type:    MemberJoined,
+				Time:    start.Add(time.Duration(i) * time.Minute),
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	until := start.Add(9 * time.Minute)
+	events, err := store.Events(1, until)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 10 {
+		t.Errorf("got %d events, want 10", len(events))
+	}
+	// events stay in the log until they are dropped
+	again, err := store.Events(1, until)
+	if err != nil || len(again) != 10 {
+		t.Errorf("got %d events, %v on the second read", len(again), err)
+	}
+	if err := store.DropEvents(1, until); err != nil {
+		t.Fatal(err)
+	}
+	rest, err := store.Events(1, start.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rest) != 10 {
+		t.Errorf("got %d events left, want 10", len(rest))
+	}
+	for _, e := range rest {
+		if !e.Time.After(until) {
+			t.Errorf("event of user %d is not dropped", e.UserId)
+		}
+	}
+
+	if _, err := os.Stat(store.eventsPath(1) + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("lock file is left: %v", err)
+	}
+	none, err := store.Events(2, start)
+	if err != nil || none != nil {
+		t.Errorf("got %v, %v for a community without events", none, err)
+	}
+	if err := store.DropEvents(2, start); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	prevTime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	curTime := prevTime.Add(time.Hour)
+	at := func(minute int) time.Time {
+		return prevTime.Add(time.Duration(minute) * time.Minute)
+	}
+	event := func(userId int, typ MembershipChangeType, minute int) MembershipChange {
+		return MembershipChange{GroupId: 1, UserId: userId, Type: typ, Time: at(minute), FromCallback: true}
+	}
+	found := func(userId int, typ MembershipChangeType) MembershipChange {
+		return MembershipChange{GroupId: 1, UserId: userId, Type: typ, Time: curTime}
+	}
+
+	tests := []struct {
+		name      string
+		prev, cur []int
+		events    []MembershipChange
+		want      []MembershipChange
+	}{
+		{
+			"no changes",
+			[]int{1, 2}, []int{1, 2},
+			nil,
+			nil,
+		},
+		{
+			"join and leave without events",
+			[]int{1, 2}, []int{2, 3},
+			nil,
+			[]MembershipChange{found(3, MemberJoined), found(1, MemberLeft)},
+		},
+		{
+			"events give the time",
+			[]int{1, 2}, []int{2, 3},
+			[]MembershipChange{event(1, MemberLeft, 20), event(3, MemberJoined, 10)},
+			[]MembershipChange{event(3, MemberJoined, 10), event(1, MemberLeft, 20)},
+		},
+		{
+			"joined and left between snapshots",
+			[]int{1}, []int{1},
+			[]MembershipChange{event(5, MemberLeft, 30), event(5, MemberJoined, 10)},
+			[]MembershipChange{event(5, MemberJoined, 10), event(5, MemberLeft, 30)},
+		},
+		{
+			"left and came back between snapshots",
+			[]int{1}, []int{1},
+			[]MembershipChange{event(1, MemberLeft, 10), event(1, MemberJoined, 20)},
+			[]MembershipChange{event(1, MemberLeft, 10), event(1, MemberJoined, 20)},
+		},
+		{
+			"duplicate events",
+			[]int{1}, []int{1, 2},
+			[]MembershipChange{event(2, MemberJoined, 10), event(2, MemberJoined, 11), event(3, MemberJoined, 12), event(3, MemberJoined, 13), event(3, MemberLeft, 14)},
+			[]MembershipChange{event(2, MemberJoined, 10), event(3, MemberJoined, 12), event(3, MemberLeft, 14)},
+		},
+		{
+			"event contradicts the snapshots",
+			[]int{1}, []int{},
+			[]MembershipChange{event(1, MemberJoined, 10)},
+			[]MembershipChange{found(1, MemberLeft)},
+		},
+	}
+	for _, tt := range tests {
+		prev := &MembersSnapshot{GroupId: 1, Time: prevTime, Members: tt.prev}
+		cur := &MembersSnapshot{GroupId: 1, Time: curTime, Members: tt.cur}
+		got := reconcile(prev, cur, tt.events)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// failingStore fails to save snapshots.
+type failingStore struct {
+	*FileSnapshotStore
+}
+
+func (s failingStore) Save(snapshot *MembersSnapshot) error {
+	return errors.New("disk is full")
+}
+
+func TestTrackKeepsEventsIfSaveFails(t *testing.T) {
+	srv, _ := membersServer(t, 3, 0)
+	defer srv.Close()
+	vk := WithToken("token")
+	vk.ApiUrl = srv.URL + "/"
+
+	store := &FileSnapshotStore{Dir: t.TempDir()}
+	err := store.Save(&MembersSnapshot{GroupId: 1, Time: time.Now().Add(-time.Hour), Members: []int{1, 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = store.AppendEvent(MembershipChange{GroupId: 1, UserId: 3, Type: MemberJoined, Time: time.Now().Add(-time.Minute), FromCallback: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = NewMembersTracker(vk, failingStore{store}).Track(1)
+	if err == nil {
+		t.Fatal("got no error")
+	}
+	changes, err := NewMembersTracker(vk, store).Track(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || !changes[0].FromCallback {
+		t.Errorf("got %+v, want the join event", changes)
+	}
+	left, err := store.Events(1, time.Now())
+	if err != nil || len(left) != 0 {
+		t.Errorf("got %d events, %v left in the log", len(left), err)
+	}
+}
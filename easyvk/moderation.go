@@ -0,0 +1,695 @@
+package easyvk
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+)
+
+// CommentSource is a type of the object the comment is left on.
+type CommentSource string
+
+const (
+	CommentSourceWall   CommentSource = "wall"
+	CommentSourcePhoto  CommentSource = "photo"
+	CommentSourceVideo  CommentSource = "video"
+	CommentSourceMarket CommentSource = "market"
+	CommentSourceBoard  CommentSource = "board"
+)
+
+// commentEvents are events of new comments by their sources.
+var commentEvents = map[CallbackEventType]CommentSource{
+	WallReplyNewEvent:     CommentSourceWall,
+	PhotoCommentNewEvent:  CommentSourcePhoto,
+	VideoCommentNewEvent:  CommentSourceVideo,
+	MarketCommentNewEvent: CommentSourceMarket,
+	BoardPostNewEvent:     CommentSourceBoard,
+}
+
+// A ModeratedComment describes a comment checked by the Moderator.
+type ModeratedComment struct {
+	Source CommentSource `json:"source"`
+	// Owner of the object, negative for communities.
+	OwnerID int `json:"owner_id"`
+	// ID of the post, photo, video, product or topic.
+	ObjectID  int       `json:"object_id"`
+	CommentID int       `json:"comment_id"`
+	FromID    int       `json:"from_id"`
+	Date      time.Time `json:"date"`
+	Text      string    `json:"text"`
+}
+
+// CommentFromEvent returns the comment of the new comment event.
+// It returns false for other events.
+func CommentFromEvent(e CallbackEvent) (*ModeratedComment, bool, error) {
+	source, ok := commentEvents[e.Type]
+	if !ok {
+		return nil, false, nil
+	}
+
+	var obj struct {
+		ID            int      `json:"id"`
+		FromID        int      `json:"from_id"`
+		Date          UnixTime `json:"date"`
+		Text          string   `json:"text"`
+		PostID        int      `json:"post_id"`
+		PostOwnerID   int      `json:"post_owner_id"`
+		PhotoID       int      `json:"photo_id"`
+		PhotoOwnerID  int      `json:"photo_owner_id"`
+		VideoID       int      `json:"video_id"`
+		VideoOwnerID  int      `json:"video_owner_id"`
+		ItemID        int      `json:"item_id"`
+		MarketOwnerID int      `json:"market_owner_id"`
+		TopicID       int      `json:"topic_id"`
+		TopicOwnerID  int      `json:"topic_owner_id"`
+	}
+	err := json.Unmarshal(e.Object, &obj)
+	if err != nil {
+		return nil, false, err
+	}
+
+	c := &ModeratedComment{
+		Source:    source,
+		CommentID: obj.ID,
+		FromID:    obj.FromID,
+		Date:      obj.Date.Time,
+		Text:      obj.Text,
+	}
+	switch source {
+	case CommentSourceWall:
+		c.OwnerID, c.ObjectID = obj.PostOwnerID, obj.PostID
+	case CommentSourcePhoto:
+		c.OwnerID, c.ObjectID = obj.PhotoOwnerID, obj.PhotoID
+	case CommentSourceVideo:
+		c.OwnerID, c.ObjectID = obj.VideoOwnerID, obj.VideoID
+	case CommentSourceMarket:
+		c.OwnerID, c.ObjectID = obj.MarketOwnerID, obj.ItemID
+	case CommentSourceBoard:
+		c.OwnerID, c.ObjectID = obj.TopicOwnerID, obj.TopicID
+	}
+	// events come only for objects of the community
+	if c.OwnerID == 0 {
+		c.OwnerID = -e.GroupID
+	}
+	return c, true, nil
+}
+
+// A ModerationRule describes a check of the comment.
+type ModerationRule interface {
+	// Match reports whether the comment breaks the rule
+	// and describes the violation.
+	Match(c *ModeratedComment) (string, bool)
+}
+
+// A RegexpRule matches comments with the text matched by Pattern.
+type RegexpRule struct {
+	Pattern *regexp.Regexp
+}
+
+// Match implements ModerationRule.
+func (r *RegexpRule) Match(c *ModeratedComment) (string, bool) {
+	found := r.Pattern.FindString(c.Text)
+	if found == "" {
+		return "", false
+	}
+	return fmt.Sprintf("matches %s: %q", r.Pattern, found), true
+}
+
+// linkPattern finds links with or without the scheme.
+// Groups are the scheme, the host, its top-level domain and the rest.
+var linkPattern = regexp.MustCompile(`(?i)(https?://)?((?:[\p{L}\d](?:[\p{L}\d-]*[\p{L}\d])?\.)+(\p{L}{2,24}))([/:?#][^\s]*)?`)
+
+// linkTLDs are top-level domains which make a link without
+// the scheme or the path, so that "ok.thanks" is not a link.
+var linkTLDs = map[string]bool{
+	"ru": true, "рф": true, "su": true, "ua": true, "by": true, "kz": true,
+	"com": true, "net": true, "org": true, "info": true, "biz": true,
+	"io": true, "me": true, "co": true, "cc": true, "tv": true, "ly": true,
+	"gl": true, "to": true, "app": true, "dev": true, "xyz": true,
+	"online": true, "site": true, "shop": true, "store": true, "top": true,
+	"club": true, "pro": true, "link": true, "click": true,
+	"de": true, "uk": true, "us": true, "eu": true,
+}
+
+// A LinkRule matches comments with links to domains
+// which are not allowed.
+type LinkRule struct {
+	// Allowed domains along with their subdomains, like vk.com.
+	Allowed []string
+}
+
+// Match implements ModerationRule.
+func (r *LinkRule) Match(c *ModeratedComment) (string, bool) {
+	for _, m := range linkPattern.FindAllStringSubmatch(c.Text, -1) {
+		scheme, host, tld, rest := m[1], strings.ToLower(m[2]), strings.ToLower(m[3]), m[4]
+		if scheme == "" && !strings.HasPrefix(rest, "/") && !linkTLDs[tld] {
+			continue
+		}
+		host = strings.TrimPrefix(host, "www.")
+		if !r.allowed(host) {
+			return fmt.Sprintf("link to %s", host), true
+		}
+	}
+	return "", false
+}
+
+func (r *LinkRule) allowed(host string) bool {
+	for _, domain := range r.Allowed {
+		domain = strings.ToLower(domain)
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// A StopWordsRule matches comments containing any of Words,
+// case insensitive. A word matches only as a whole word,
+// it may be a phrase of several words.
+type StopWordsRule struct {
+	Words []string
+}
+
+// Match implements ModerationRule.
+func (r *StopWordsRule) Match(c *ModeratedComment) (string, bool) {
+	text := normalizeWords(c.Text)
+	for _, w := range r.Words {
+		n := normalizeWords(w)
+		if strings.TrimSpace(n) != "" && strings.Contains(text, n) {
+			return fmt.Sprintf("stop word %q", w), true
+		}
+	}
+	return "", false
+}
+
+// normalizeWords returns lower case words of s
+// separated and surrounded by spaces.
+func normalizeWords(s string) string {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	return " " + strings.Join(words, " ") + " "
+}
+
+// A FloodRule matches the comment if its author has left
+// more than Limit comments within Period, the comment included.
+// Comments from all sources are counted.
+type FloodRule struct {
+	Limit  int
+	Period time.Duration
+
+	mu   sync.Mutex
+	seen map[int][]time.Time
+}
+
+// Match implements ModerationRule.
+func (r *FloodRule) Match(c *ModeratedComment) (string, bool) {
+	now := c.Date
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.seen == nil {
+		r.seen = map[int][]time.Time{}
+	}
+	r.forget(now)
+	times := append(r.seen[c.FromID], now)
+	r.seen[c.FromID] = times
+
+	if len(times) <= r.Limit {
+		return "", false
+	}
+	return fmt.Sprintf("%d comments in %s", len(times), r.Period), true
+}
+
+// forget drops comments left before the period.
+func (r *FloodRule) forget(now time.Time) {
+	since := now.Add(-r.Period)
+	for user, times := range r.seen {
+		i := 0
+		for i < len(times) && !times[i].After(since) {
+			i++
+		}
+		if i == len(times) {
+			delete(r.seen, user)
+		} else {
+			r.seen[user] = times[i:]
+		}
+	}
+}
+
+// ModerationAction is a set of actions applied to the comment.
+type ModerationAction int
+
+const (
+	// ModerationWarn replies to the comment with the warning
+	ModerationWarn ModerationAction = 1 << iota
+	// ModerationDelete deletes the comment
+	ModerationDelete
+	// ModerationBan bans the author in the community
+	ModerationBan
+)
+
+var moderationActionNames = []struct {
+	action ModerationAction
+	name   string
+}{
+	{ModerationWarn, "warn"},
+	{ModerationDelete, "delete"},
+	{ModerationBan, "ban"},
+}
+
+func (a ModerationAction) String() string {
+	var names []string
+	for _, n := range moderationActionNames {
+		if a&n.action != 0 {
+			names = append(names, n.name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// A ModerationRuleSet describes actions applied
+// to comments which break any of the rules.
+type ModerationRuleSet struct {
+	Name string
+	// Sources checked by the set, all sources if empty.
+	Sources []CommentSource
+	Rules   []ModerationRule
+	Action  ModerationAction
+	// Reply to the comment for ModerationWarn.
+	Warning string
+	// Reason of the ban for ModerationBan.
+	BanReason BanReason
+	// Duration of the ban, zero for the permanent ban.
+	BanDuration time.Duration
+	// Comment of the ban shown to the user if it's set.
+	BanComment string
+}
+
+func (s *ModerationRuleSet) checks(source CommentSource) bool {
+	if len(s.Sources) == 0 {
+		return true
+	}
+	for _, src := range s.Sources {
+		if src == source {
+			return true
+		}
+	}
+	return false
+}
+
+// match checks all rules, so that rules with state like
+// FloodRule count every comment, and returns the first violation.
+func (s *ModerationRuleSet) match(c *ModeratedComment) (string, bool) {
+	var violation string
+	matched := false
+	for _, rule := range s.Rules {
+		v, ok := rule.Match(c)
+		if ok && !matched {
+			violation = v
+			matched = true
+		}
+	}
+	return violation, matched
+}
+
+// A ModerationRecord describes a comment which broke
+// the rule set and actions applied to it.
+type ModerationRecord struct {
+	Time      time.Time        `json:"time"`
+	Comment   ModeratedComment `json:"comment"`
+	RuleSet   string           `json:"rule_set"`
+	Violation string           `json:"violation"`
+	// Actions applied successfully.
+	Actions []string `json:"actions"`
+	// Errors of failed actions.
+	Errors []string `json:"errors,omitempty"`
+	DryRun bool     `json:"dry_run,omitempty"`
+}
+
+// A ModerationAuditLog describes a storage of moderation records.
+type ModerationAuditLog interface {
+	Write(r ModerationRecord) error
+}
+
+// A FileAuditLog appends records to the file as json lines.
+type FileAuditLog struct {
+	Path string
+
+	mu sync.Mutex
+}
+
+// Write appends the record to the file.
+func (l *FileAuditLog) Write(r ModerationRecord) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	data = append(data, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	f, err := os.OpenFile(l.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	closeErr := f.Close()
+	if err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// moderatedCommentsLimit is how many last comments the Moderator
+// remembers to skip events VK sends again.
+const moderatedCommentsLimit = 10000
+
+// warnMethods and deleteMethods are methods used for actions by sources.
+var (
+	warnMethods = map[CommentSource]string{
+		CommentSourceWall:   "wall.createComment",
+		CommentSourcePhoto:  "photos.createComment",
+		CommentSourceVideo:  "video.createComment",
+		CommentSourceMarket: "market.createComment",
+		CommentSourceBoard:  "board.createComment",
+	}
+	deleteMethods = map[CommentSource]string{
+		CommentSourceWall:   "wall.deleteComment",
+		CommentSourcePhoto:  "photos.deleteComment",
+		CommentSourceVideo:  "video.deleteComment",
+		CommentSourceMarket: "market.deleteComment",
+		CommentSourceBoard:  "board.deleteComment",
+	}
+	allCommentSources = []CommentSource{
+		CommentSourceWall,
+		CommentSourcePhoto,
+		CommentSourceVideo,
+		CommentSourceMarket,
+		CommentSourceBoard,
+	}
+)
+
+// A Moderator checks new comments in the community against rule sets.
+// It deletes the comments, warns or bans their authors
+// and keeps the audit log of what it has done.
+// Every comment is moderated once, events VK sends again are skipped.
+type Moderator struct {
+	vk *VK
+	// If set, warnings and bans are sent with it. Only wall comments
+	// can be created with a community token, warnings on photos, videos,
+	// products and topics and bans need a user token of a community admin.
+	UserVK  *VK
+	GroupID int
+	// Sets are checked in order, only the first broken set is applied.
+	RuleSets []ModerationRuleSet
+	// Users whose comments are not checked, like managers.
+	// Comments of communities are never checked.
+	Exempt map[int]bool
+	// Records are written to the log if it's not nil.
+	Log ModerationAuditLog
+	// Only write records without applying actions.
+	DryRun bool
+
+	mu sync.Mutex
+	// keys of the last moderated comments, oldest first
+	seen      map[string]bool
+	seenOrder []string
+}
+
+// NewModerator returns a moderator of the community
+// which writes records to the log.
+func NewModerator(vk *VK, groupID int, log ModerationAuditLog) *Moderator {
+	return &Moderator{
+		vk:      vk,
+		GroupID: groupID,
+		Exempt:  map[int]bool{},
+		Log:     log,
+	}
+}
+
+// HandleCallbackEvent moderates the comment of the new comment event.
+// Other events are ignored, so it can be called for every event
+// from CallbackHandler or GroupLongPoll. Actions take several requests,
+// so call it in another goroutine from CallbackHandler.Handle,
+// otherwise VK may not get "ok" in time and send the event again.
+func (m *Moderator) HandleCallbackEvent(e CallbackEvent) error {
+	c, ok, err := CommentFromEvent(e)
+	if err != nil || !ok {
+		return err
+	}
+	_, err = m.Moderate(c)
+	return err
+}
+
+// Moderate applies actions of the first rule set the comment breaks.
+// It returns nil if the comment breaks no set or has been moderated
+// already. Failed actions don't stop the others, the first error
+// is returned with the record.
+func (m *Moderator) Moderate(c *ModeratedComment) (*ModerationRecord, error) {
+	if c.FromID <= 0 || m.Exempt[c.FromID] {
+		return nil, nil
+	}
+	if !m.remember(c) {
+		return nil, nil
+	}
+
+	for i := range m.RuleSets {
+		set := &m.RuleSets[i]
+		if !set.checks(c.Source) {
+			continue
+		}
+		violation, ok := set.match(c)
+		if !ok {
+			continue
+		}
+
+		record := &ModerationRecord{
+			Time:      time.Now(),
+			Comment:   *c,
+			RuleSet:   set.Name,
+			Violation: violation,
+			DryRun:    m.DryRun,
+		}
+		var firstErr error
+		for _, n := range moderationActionNames {
+			if set.Action&n.action == 0 {
+				continue
+			}
+			var err error
+			if !m.DryRun {
+				err = m.apply(n.action, set, c)
+			}
+			if err != nil {
+				record.Errors = append(record.Errors, fmt.Sprintf("%s: %v", n.name, err))
+				if firstErr == nil {
+					firstErr = err
+				}
+			} else {
+				record.Actions = append(record.Actions, n.name)
+			}
+		}
+
+		if m.Log != nil {
+			err := m.Log.Write(*record)
+			if err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		return record, firstErr
+	}
+	return nil, nil
+}
+
+// remember adds the comment to the seen ones,
+// it returns false if the comment is there already.
+func (m *Moderator) remember(c *ModeratedComment) bool {
+	key := fmt.Sprintf("%s%d_%d_%d", c.Source, c.OwnerID, c.ObjectID, c.CommentID)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.seen == nil {
+		m.seen = map[string]bool{}
+	}
+	if m.seen[key] {
+		return false
+	}
+	if len(m.seenOrder) >= moderatedCommentsLimit {
+		delete(m.seen, m.seenOrder[0])
+		m.seenOrder = m.seenOrder[1:]
+	}
+	m.seen[key] = true
+	m.seenOrder = append(m.seenOrder, key)
+	return true
+}
+
+// CheckTokens returns an error if the tokens can't make
+// actions of the rule sets, e.g. when warnings on photos are
+// to be sent with a community token. Call it before moderating,
+// it doesn't send requests.
+func (m *Moderator) CheckTokens() error {
+	if m.DryRun {
+		return nil
+	}
+	var problems []string
+	for _, set := range m.RuleSets {
+		sources := set.Sources
+		if len(sources) == 0 {
+			sources = allCommentSources
+		}
+		for _, source := range sources {
+			if set.Action&ModerationWarn != 0 {
+				err := m.userVK().CanCall(warnMethods[source])
+				if err != nil {
+					problems = append(problems, fmt.Sprintf("%s: warn on %s comments: %v", set.Name, source, err))
+				}
+			}
+			if set.Action&ModerationDelete != 0 {
+				err := m.vk.CanCall(deleteMethods[source])
+				if err != nil {
+					problems = append(problems, fmt.Sprintf("%s: delete %s comments: %v", set.Name, source, err))
+				}
+			}
+		}
+		if set.Action&ModerationBan != 0 {
+			err := m.userVK().CanCall("groups.banUser")
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%s: ban: %v", set.Name, err))
+			}
+		}
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("moderator can't apply actions: %s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// userVK returns VK to send warnings and bans with.
+func (m *Moderator) userVK() *VK {
+	if m.UserVK != nil {
+		return m.UserVK
+	}
+	return m.vk
+}
+
+// canCall checks that userVK can call the method.
+func (m *Moderator) canCall(method string) error {
+	err := m.userVK().CanCall(method)
+	if err != nil {
+		return fmt.Errorf("%v, set Moderator.UserVK to a user token of a community admin", err)
+	}
+	return nil
+}
+
+func (m *Moderator) apply(action ModerationAction, set *ModerationRuleSet, c *ModeratedComment) error {
+	switch action {
+	case ModerationWarn:
+		return m.warn(c, set.Warning)
+	case ModerationDelete:
+		return m.deleteComment(c)
+	case ModerationBan:
+		p := BanUserParams{
+			GroupID:        m.GroupID,
+			UserID:         c.FromID,
+			Reason:         set.BanReason,
+			Comment:        set.BanComment,
+			CommentVisible: set.BanComment != "",
+		}
+		if set.BanDuration > 0 {
+			p.EndDate = time.Now().Add(set.BanDuration)
+		}
+		err := m.canCall("groups.banUser")
+		if err != nil {
+			return err
+		}
+		_, err = m.userVK().Groups.BanUser(p)
+		return err
+	}
+	return fmt.Errorf("unknown moderation action %d", action)
+}
+
+// warn replies to the comment on behalf of the community.
+func (m *Moderator) warn(c *ModeratedComment, text string) error {
+	vk := m.userVK()
+	if method, ok := warnMethods[c.Source]; ok {
+		err := m.canCall(method)
+		if err != nil {
+			return err
+		}
+	}
+
+	var err error
+	switch c.Source {
+	case CommentSourceWall:
+		_, err = vk.Wall.CreateComment(CreateCommentParams{
+			OwnerID:        c.OwnerID,
+			PostID:         c.ObjectID,
+			FromGroup:      1,
+			Message:        text,
+			ReplyToComment: c.CommentID,
+		})
+	case CommentSourcePhoto:
+		_, err = vk.Photos.CreateComment(PhotosCreateCommentParams{
+			OwnerID:        c.OwnerID,
+			PhotoID:        c.ObjectID,
+			Message:        text,
+			FromGroup:      true,
+			ReplyToComment: c.CommentID,
+		})
+	case CommentSourceVideo:
+		_, err = vk.Video.CreateComment(VideoCreateCommentParams{
+			OwnerID:        c.OwnerID,
+			VideoID:        c.ObjectID,
+			Message:        text,
+			FromGroup:      true,
+			ReplyToComment: c.CommentID,
+		})
+	case CommentSourceMarket:
+		_, err = vk.Market.CreateComment(MarketCreateCommentParams{
+			OwnerID:        c.OwnerID,
+			ItemID:         c.ObjectID,
+			Message:        text,
+			FromGroup:      true,
+			ReplyToComment: c.CommentID,
+		})
+	case CommentSourceBoard:
+		// topics have no replies, so the author is mentioned
+		_, err = vk.Board.CreateComment(BoardCreateCommentParams{
+			GroupID:   -c.OwnerID,
+			TopicID:   c.ObjectID,
+			Message:   fmt.Sprintf("[id%d|@id%d], %s", c.FromID, c.FromID, text),
+			FromGroup: true,
+		})
+	default:
+		err = fmt.Errorf("unknown comment source %q", c.Source)
+	}
+	return err
+}
+
+// deleteComment deletes the comment through the section of its source.
+func (m *Moderator) deleteComment(c *ModeratedComment) error {
+	var err error
+	switch c.Source {
+	case CommentSourceWall:
+		_, err = m.vk.Wall.DeleteComment(c.OwnerID, c.CommentID)
+	case CommentSourcePhoto:
+		_, err = m.vk.Photos.DeleteComment(c.OwnerID, c.CommentID)
+	case CommentSourceVideo:
+		_, err = m.vk.Video.DeleteComment(c.OwnerID, c.CommentID)
+	case CommentSourceMarket:
+		_, err = m.vk.Market.DeleteComment(c.OwnerID, c.CommentID)
+	case CommentSourceBoard:
+		_, err = m.vk.Board.DeleteComment(-c.OwnerID, c.ObjectID, c.CommentID)
+	default:
+		err = fmt.Errorf("unknown comment source %q", c.Source)
+	}
+	return err
+}
@@ -0,0 +1,270 @@
+package easyvk
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestModeratorSkipsRepeatedComments(t *testing.T) {
+	m := NewModerator(WithToken(""), 1, nil)
+	m.DryRun = true
+	m.RuleSets = []ModerationRuleSet{{
+		Name:   "flood",
+		Rules:  []ModerationRule{&FloodRule{Limit: 1, Period: time.Hour}},
+		Action: ModerationDelete,
+	}}
+
+	comment := func(id int) *ModeratedComment {
+		return &ModeratedComment{
+			Source:    CommentSourcePhoto,
+			OwnerID:   -1,
+			ObjectID:  10,
+			CommentID: id,
+			FromID:    5,
+			Date:      time.Date(2020, 1, 1, 0, id, 0, 0, time.UTC),
+		}
+	}
+	for i, want := range []bool{false, false, false, true} {
+		// the first comment comes three times
+		id := 1
+		if i == 3 {
+			id = 2
+		}
+		record, err := m.Moderate(comment(id))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if (record != nil) != want {
+			t.Errorf("event %d: got record %+v, want %v", i, record, want)
+		}
+	}
+}
+
+func TestModeratorRemembersLimitedComments(t *testing.T) {
+	m := &Moderator{}
+	for i := 0; i < moderatedCommentsLimit+1; i++ {
+		m.remember(&ModeratedComment{Source: CommentSourceWall, CommentID: i})
+	}
+	if len(m.seen) != moderatedCommentsLimit || len(m.seenOrder) != moderatedCommentsLimit {
+		t.Errorf("got %d comments", len(m.seen))
+	}
+	if !m.remember(&ModeratedComment{Source: CommentSourceWall, CommentID: 0}) {
+		t.Error("the oldest comment is not forgotten")
+	}
+	if m.remember(&ModeratedComment{Source: CommentSourceWall, CommentID: moderatedCommentsLimit}) {
+		t.Error("the last comment is forgotten")
+	}
+}
+
+func TestModeratorCheckTokens(t *testing.T) {
+	group := WithToken("group")
+	group.TokenKind = GroupToken
+	user := WithToken("user")
+	user.TokenKind = UserToken
+
+	m := NewModerator(group, 1, nil)
+	m.RuleSets = []ModerationRuleSet{
+		{Name: "wall", Sources: []CommentSource{CommentSourceWall}, Action: ModerationWarn | ModerationDelete},
+		{Name: "all", Action: ModerationDelete},
+	}
+	if err := m.CheckTokens(); err != nil {
+		t.Errorf("got %v", err)
+	}
+
+	// community tokens can't ban
+	m.RuleSets[0].Action |= ModerationBan
+	err := m.CheckTokens()
+	if err == nil || !strings.Contains(err.Error(), "wall: ban") {
+		t.Errorf("got %v", err)
+	}
+	err = m.apply(ModerationBan, &m.RuleSets[0], &ModeratedComment{Source: CommentSourceWall, FromID: 1})
+	if err == nil || !strings.Contains(err.Error(), "Moderator.UserVK") {
+		t.Errorf("got %v", err)
+	}
+
+	m.RuleSets = append(m.RuleSets, ModerationRuleSet{Name: "photos", Sources: []CommentSource{CommentSourcePhoto}, Action: ModerationWarn})
+	err = m.CheckTokens()
+	if err == nil || !strings.Contains(err.Error(), "photos: warn on photo comments") {
+		t.Errorf("got %v", err)
+	}
+	_, err = m.Moderate(&ModeratedComment{Source: CommentSourcePhoto, FromID: 1})
+	if err != nil {
+		t.Errorf("comment without violations: %v", err)
+	}
+	err = m.warn(&ModeratedComment{Source: CommentSourcePhoto, FromID: 1}, "stop")
+	if err == nil || !strings.Contains(err.Error(), "Moderator.UserVK") {
+		t.Errorf("got %v", err)
+	}
+
+	m.UserVK = user
+	if err := m.CheckTokens(); err != nil {
+		t.Errorf("got %v with user token", err)
+	}
+}
+
+func TestRegexpRule(t *testing.T) {
+	rule := &RegexpRule{Pattern: regexp.MustCompile(`(?i)\bfree\s+money\b`)}
+	tests := []struct {
+		text  string
+		match bool
+	}{
+		{"Get FREE  money now", true},
+		{"freemoney", false},
+		{"money is not free", false},
+	}
+	for _, tt := range tests {
+		violation, ok := rule.Match(&ModeratedComment{Text: tt.text})
+		if ok != tt.match {
+			t.Errorf("%q: got %v, want %v", tt.text, ok, tt.match)
+		}
+		if ok && !strings.Contains(violation, "FREE  money") {
+			t.Errorf("%q: got violation %q", tt.text, violation)
+		}
+	}
+}
+
+func TestLinkRule(t *testing.T) {
+	rule := &LinkRule{Allowed: []string{"vk.com", "Example.org"}}
+	tests := []struct {
+		text string
+		host string
+	}{
+		{"see https://vk.com/wall-1_2", ""},
+		{"see m.vk.com/id1 and WWW.EXAMPLE.ORG", ""},
+		{"go to https://spam.example.com/x", "spam.example.com"},
+		{"go to spam.ru", "spam.ru"},
+		{"go to www.spam.ru now", "spam.ru"},
+		{"http://localhost.test", "localhost.test"},
+		{"open shop.example/catalog", "shop.example"},
+		{"vk.com.evil.net", "vk.com.evil.net"},
+		{"notvk.com", "notvk.com"},
+		{"ok.thanks, see you", ""},
+		{"end of sentence.Next one:)", ""},
+		{"version 1.2.3", ""},
+	}
+	for _, tt := range tests {
+		violation, ok := rule.Match(&ModeratedComment{Text: tt.text})
+		if ok != (tt.host != "") {
+			t.Errorf("%q: got %v (%s), want link to %q", tt.text, ok, violation, tt.host)
+			continue
+		}
+		if ok && violation != "link to "+tt.host {
+			t.Errorf("%q: got %q, want link to %s", tt.text, violation, tt.host)
+		}
+	}
+}
+
+func TestStopWordsRule(t *testing.T) {
+	rule := &StopWordsRule{Words: []string{"spam", "Buy Now", "  "}}
+	tests := []struct {
+		text  string
+		match bool
+	}{
+		{"this is SPAM!", true},
+		{"spam", true},
+		{"buy   now, please", true},
+		{"buy-now", true},
+		{"spammer here", false},
+		{"antispam", false},
+		{"buy nowhere", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		_, ok := rule.Match(&ModeratedComment{Text: tt.text})
+		if ok != tt.match {
+			t.Errorf("%q: got %v, want %v", tt.text, ok, tt.match)
+		}
+	}
+}
+
+func TestFloodRule(t *testing.T) {
+	rule := &FloodRule{Limit: 2, Period: time.Minute}
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		fromID int
+		second int
+		match  bool
+	}{
+		{1, 0, false},
+		{1, 10, false},
+		{2, 15, false},
+		{1, 20, true},
+		// the first comment of user 1 is out of the period
+		{1, 60, true},
+		{1, 79, true},
+		// flagged comments are counted too
+		{1, 119, true},
+		{2, 80, false},
+		{1, 200, false},
+	}
+	for i, tt := range tests {
+		c := &ModeratedComment{FromID: tt.fromID, Date: start.Add(time.Duration(tt.second) * time.Second)}
+		_, ok := rule.Match(c)
+		if ok != tt.match {
+			t.Errorf("comment %d of user %d at %ds: got %v, want %v", i, tt.fromID, tt.second, ok, tt.match)
+		}
+	}
+}
+
+func TestCommentFromEvent(t *testing.T) {
+	tests := []struct {
+		typ    CallbackEventType
+		object string
+		want   ModeratedComment
+	}{
+		{
+			WallReplyNewEvent,
+			`{"id": 3, "from_id": 5, "date": 1577836800, "text": "hi", "post_id": 2, "post_owner_id": -1}`,
+			ModeratedComment{Source: CommentSourceWall, OwnerID: -1, ObjectID: 2, CommentID: 3, FromID: 5, Text: "hi"},
+		},
+		{
+			PhotoCommentNewEvent,
+			`{"id": 3, "from_id": 5, "date": 1577836800, "text": "hi", "photo_id": 4, "photo_owner_id": -1}`,
+			ModeratedComment{Source: CommentSourcePhoto, OwnerID: -1, ObjectID: 4, CommentID: 3, FromID: 5, Text: "hi"},
+		},
+		{
+			VideoCommentNewEvent,
+			`{"id": 3, "from_id": 5, "date": 1577836800, "text": "hi", "video_id": 6, "video_owner_id": -1}`,
+			ModeratedComment{Source: CommentSourceVideo, OwnerID: -1, ObjectID: 6, CommentID: 3, FromID: 5, Text: "hi"},
+		},
+		{
+			MarketCommentNewEvent,
+			`{"id": 3, "from_id": 5, "date": 1577836800, "text": "hi", "item_id": 7, "market_owner_id": -1}`,
+			ModeratedComment{Source: CommentSourceMarket, OwnerID: -1, ObjectID: 7, CommentID: 3, FromID: 5, Text: "hi"},
+		},
+		{
+			BoardPostNewEvent,
+			`{"id": 3, "from_id": 5, "date": 1577836800, "text": "hi", "topic_id": 8, "topic_owner_id": -1}`,
+			ModeratedComment{Source: CommentSourceBoard, OwnerID: -1, ObjectID: 8, CommentID: 3, FromID: 5, Text: "hi"},
+		},
+		{
+			// the owner is the community of the event if it's not sent
+			BoardPostNewEvent,
+			`{"id": 3, "from_id": 5, "date": 1577836800, "text": "hi", "topic_id": 8}`,
+			ModeratedComment{Source: CommentSourceBoard, OwnerID: -9, ObjectID: 8, CommentID: 3, FromID: 5, Text: "hi"},
+		},
+	}
+	for _, tt := range tests {
+		c, ok, err := CommentFromEvent(CallbackEvent{Type: tt.typ, Object: json.RawMessage(tt.object), GroupID: 9})
+		if err != nil || !ok {
+			t.Errorf("%s: got %v, %v", tt.typ, ok, err)
+			continue
+		}
+		tt.want.Date = time.Unix(1577836800, 0)
+		if !c.Date.Equal(tt.want.Date) {
+			t.Errorf("%s: got date %s", tt.typ, c.Date)
+		}
+		c.Date = tt.want.Date
+		if *c != tt.want {
+			t.Errorf("%s: got %+v, want %+v", tt.typ, *c, tt.want)
+		}
+	}
+
+	c, ok, err := CommentFromEvent(CallbackEvent{Type: GroupJoinEvent, Object: json.RawMessage(`{"user_id": 1}`)})
+	if c != nil || ok || err != nil {
+		t.Errorf("got %+v, %v, %v for another event", c, ok, err)
+	}
+}
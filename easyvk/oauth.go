@@ -0,0 +1,338 @@
+package easyvk
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	oauthAuthorizeURL = "https://oauth.vk.com/authorize"
+	oauthTokenURL     = "https://oauth.vk.com/access_token"
+	oauthStateCookie  = "easyvk_oauth_state"
+)
+
+// An OAuthConfig describes an application
+// that uses Authorization Code Flow.
+// https://vk.com/dev/authcode_flow_user
+type OAuthConfig struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURI  string
+	Scope        Scope
+	// If set, community tokens with GroupScope
+	// are requested for these communities instead of the user token.
+	// https://vk.com/dev/authcode_flow_group
+	GroupIDs   []int
+	GroupScope GroupScope
+	// one of: page (default), popup, mobile
+	Display string
+	// Ask the user for permissions even if they have been granted.
+	Revoke bool
+	// Client to exchange the code with, http.DefaultClient by default.
+	Client *http.Client
+	// If set, received tokens are saved to the store,
+	// use LoadToken and LoadGroupToken to get them later.
+	Store TokenStore
+}
+
+// An OAuthToken describes tokens received by the code exchange.
+type OAuthToken struct {
+	AccessToken string `json:"access_token"`
+	// Lifetime of the token in seconds, 0 if the token never expires.
+	ExpiresIn  int        `json:"expires_in"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	UserID     int        `json:"user_id"`
+	Scope      Scope      `json:"-"`
+	GroupScope GroupScope `json:"-"`
+	// Email is set only if the email scope has been requested.
+	Email string `json:"email"`
+	// Community tokens by community id.
+	GroupTokens map[int]string `json:"group_tokens"`
+}
+
+// VK returns VK object with the user token
+// or nil if there is no user token, e.g. when
+// only community tokens have been requested.
+func (t *OAuthToken) VK() *VK {
+	if t.AccessToken == "" {
+		return nil
+	}
+	vk := WithToken(t.AccessToken)
+	vk.TokenKind = UserToken
+	vk.Scope = t.Scope
+	return vk
+}
+
+// GroupVK returns VK object with the community token
+// or nil if there is no token for the community.
+func (t *OAuthToken) GroupVK(groupID int) *VK {
+	token, ok := t.GroupTokens[groupID]
+	if !ok {
+		return nil
+	}
+	vk := WithToken(token)
+	vk.TokenKind = GroupToken
+	vk.GroupScope = t.GroupScope
+	return vk
+}
+
+// An OAuthError describes an error returned by VK OAuth server.
+type OAuthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *OAuthError) Error() string {
+	if e.Description != "" {
+		return fmt.Sprintf("oauth %s: %s", e.Code, e.Description)
+	}
+	return "oauth " + e.Code
+}
+
+// NewOAuthState returns a random string to protect the flow from CSRF.
+func NewOAuthState() (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// AuthCodeURL returns the url of the page where the user grants permissions.
+// VK will pass state back to the redirect uri along with the code.
+func (c *OAuthConfig) AuthCodeURL(state string) string {
+	query := url.Values{}
+	query.Set("client_id", c.ClientID)
+	query.Set("redirect_uri", c.RedirectURI)
+	query.Set("response_type", "code")
+	query.Set("scope", c.Scope.String())
+	query.Set("state", state)
+	query.Set("v", version)
+	if c.Display != "" {
+		query.Set("display", c.Display)
+	}
+	if c.Revoke {
+		query.Set("revoke", "1")
+	}
+	if len(c.GroupIDs) > 0 {
+		query.Set("group_ids", strings.Join(intIdsToString(c.GroupIDs), ","))
+		query.Set("scope", c.GroupScope.String())
+	}
+	return oauthAuthorizeURL + "?" + query.Encode()
+}
+
+// Exchange exchanges the code for the access token.
+// Returns *OAuthError if VK refuses to give the token.
+func (c *OAuthConfig) Exchange(code string) (*OAuthToken, error) {
+	query := url.Values{}
+	query.Set("client_id", c.ClientID)
+	query.Set("client_secret", c.ClientSecret)
+	query.Set("redirect_uri", c.RedirectURI)
+	query.Set("code", code)
+	token, err := requestOAuthToken(c.Client, query)
+	if err != nil {
+		return nil, err
+	}
+	token.Scope = c.Scope
+	token.GroupScope = c.GroupScope
+
+	if c.Store != nil {
+		err = c.saveTokens(token)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return token, nil
+}
+
+func (c *OAuthConfig) userTokenKey(userID int) string {
+	return fmt.Sprintf("oauth:%s:user:%d", c.ClientID, userID)
+}
+
+func (c *OAuthConfig) groupTokenKey(groupID int) string {
+	return fmt.Sprintf("oauth:%s:group:%d", c.ClientID, groupID)
+}
+
+func (c *OAuthConfig) saveTokens(token *OAuthToken) error {
+	if token.AccessToken != "" {
+		err := c.Store.Save(c.userTokenKey(token.UserID), &StoredToken{
+			AccessToken: token.AccessToken,
+			UserID:      token.UserID,
+			ExpiresAt:   token.ExpiresAt,
+			Kind:        UserToken,
+			Scope:       token.Scope,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for groupID, groupToken := range token.GroupTokens {
+		err := c.Store.Save(c.groupTokenKey(groupID), &StoredToken{
+			AccessToken: groupToken,
+			ExpiresAt:   token.ExpiresAt,
+			Kind:        GroupToken,
+			GroupScope:  token.GroupScope,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadToken returns VK object with the saved token of the user
+// or nil if the user has to go through the flow again.
+func (c *OAuthConfig) LoadToken(userID int) (*VK, error) {
+	return LoadToken(c.Store, c.userTokenKey(userID))
+}
+
+// LoadGroupToken returns VK object with the saved token of the community
+// or nil if the token has to be requested again.
+func (c *OAuthConfig) LoadGroupToken(groupID int) (*VK, error) {
+	return LoadToken(c.Store, c.groupTokenKey(groupID))
+}
+
+// requestOAuthToken requests oauth.vk.com/access_token with the query.
+func requestOAuthToken(client *http.Client, query url.Values) (*OAuthToken, error) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Get(oauthTokenURL + "?" + query.Encode())
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	err = json.Unmarshal(body, &fields)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := fields["error"]; ok {
+		oauthErr := &OAuthError{}
+		err = json.Unmarshal(body, oauthErr)
+		if err != nil {
+			return nil, err
+		}
+		return nil, oauthErr
+	}
+
+	token := &OAuthToken{}
+	err = json.Unmarshal(body, token)
+	if err != nil {
+		return nil, err
+	}
+	if token.ExpiresIn > 0 {
+		token.ExpiresAt = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	}
+
+	// community tokens come as access_token_<group_id>
+	for key, raw := range fields {
+		if !strings.HasPrefix(key, "access_token_") {
+			continue
+		}
+		groupID, err := strconv.Atoi(strings.TrimPrefix(key, "access_token_"))
+		if err != nil {
+			continue
+		}
+		var groupToken string
+		err = json.Unmarshal(raw, &groupToken)
+		if err != nil {
+			return nil, err
+		}
+		if token.GroupTokens == nil {
+			token.GroupTokens = map[int]string{}
+		}
+		token.GroupTokens[groupID] = groupToken
+	}
+
+	return token, nil
+}
+
+// An OAuthHandler describes an http.Handler for the redirect uri.
+// Start the flow with Redirect, it keeps state in a cookie
+// which is checked when VK redirects the user back.
+type OAuthHandler struct {
+	Config *OAuthConfig
+	// OnToken is called after the code has been exchanged.
+	// By default the user is told that access is granted,
+	// set Config.Store to keep the tokens then.
+	OnToken func(w http.ResponseWriter, r *http.Request, token *OAuthToken)
+	// OnError is called if the user denied access, state doesn't match
+	// or the exchange failed. By default responds with 400.
+	OnError func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// Redirect sends the user to the VK page where permissions are granted.
+func (h *OAuthHandler) Redirect(w http.ResponseWriter, r *http.Request) {
+	state, err := NewOAuthState()
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     oauthStateCookie,
+		Value:    state,
+		Path:     "/",
+		MaxAge:   600,
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+	http.Redirect(w, r, h.Config.AuthCodeURL(state), http.StatusFound)
+}
+
+// ServeHTTP checks the state, exchanges the code and calls OnToken.
+func (h *OAuthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	if e := query.Get("error"); e != "" {
+		h.fail(w, r, &OAuthError{Code: e, Description: query.Get("error_description")})
+		return
+	}
+
+	cookie, err := r.Cookie(oauthStateCookie)
+	state := query.Get("state")
+	if err != nil || state == "" || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(state)) != 1 {
+		h.fail(w, r, &OAuthError{Code: "invalid_state", Description: "state doesn't match"})
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:   oauthStateCookie,
+		Path:   "/",
+		MaxAge: -1,
+	})
+
+	token, err := h.Config.Exchange(query.Get("code"))
+	if err != nil {
+		h.fail(w, r, err)
+		return
+	}
+	if h.OnToken == nil {
+		fmt.Fprintln(w, "Access granted, you can close this page.")
+		return
+	}
+	h.OnToken(w, r, token)
+}
+
+func (h *OAuthHandler) fail(w http.ResponseWriter, r *http.Request, err error) {
+	if h.OnError != nil {
+		h.OnError(w, r, err)
+		return
+	}
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
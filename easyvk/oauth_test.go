@@ -0,0 +1,182 @@
+package easyvk
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// oauthServer answers the code exchange with body
+// and keeps the query of the last request.
+func oauthServer(body string, query *url.Values) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/access_token" {
+			http.NotFound(w, r)
+			return
+		}
+		*query = r.URL.Query()
+		fmt.Fprint(w, body)
+	}))
+}
+
+func oauthConfig(srv *httptest.Server) *OAuthConfig {
+	return &OAuthConfig{
+		ClientID:     "1",
+		ClientSecret: "secret",
+		RedirectURI:  "https://example.com/vk",
+		Scope:        ScopeWall,
+		GroupScope:   GroupScopeManage,
+		Client:       &http.Client{Transport: hostTransport{strings.TrimPrefix(srv.URL, "http://")}},
+	}
+}
+
+func TestOAuthExchange(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		token  string
+		groups map[int]string
+		err    string
+	}{
+		{"user token", `{"access_token": "user", "expires_in": 86400, "user_id": 7}`, "user", nil, ""},
+		{
+			"community tokens",
+			`{"access_token_1": "first", "access_token_20": "second", "access_token_x": "skip", "expires_in": 0}`,
+			"", map[int]string{1: "first", 20: "second"}, "",
+		},
+		{"error", `{"error": "invalid_grant", "error_description": "Code is expired."}`, "", nil, "oauth invalid_grant: Code is expired."},
+	}
+	for _, tt := range tests {
+		var query url.Values
+		srv := oauthServer(tt.body, &query)
+		token, err := oauthConfig(srv).Exchange("code")
+		srv.Close()
+
+		if tt.err != "" {
+			if _, ok := err.(*OAuthError); !ok || err.Error() != tt.err {
+				t.Errorf("%s: got %v, want %s", tt.name, err, tt.err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if token.AccessToken != tt.token || !reflect.DeepEqual(token.GroupTokens, tt.groups) {
+			t.Errorf("%s: got %q and %v", tt.name, token.AccessToken, token.GroupTokens)
+		}
+		if token.ExpiresAt.IsZero() != (token.ExpiresIn == 0) {
+			t.Errorf("%s: got expiry %s for %d seconds", tt.name, token.ExpiresAt, token.ExpiresIn)
+		}
+		if query.Get("code") != "code" || query.Get("client_secret") != "secret" || query.Get("redirect_uri") != "https://example.com/vk" {
+			t.Errorf("%s: got query %v", tt.name, query)
+		}
+	}
+}
+
+func TestOAuthExchangeStore(t *testing.T) {
+	var query url.Values
+	srv := oauthServer(`{"access_token": "user", "user_id": 7, "access_token_5": "group"}`, &query)
+	defer srv.Close()
+	config := oauthConfig(srv)
+	config.Store = NewMemoryTokenStore()
+
+	_, err := config.Exchange("code")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := config.Store.Load(config.userTokenKey(7))
+	if err != nil || user == nil || user.AccessToken != "user" || user.Kind != UserToken || user.Scope != ScopeWall {
+		t.Errorf("got user %+v, %v", user, err)
+	}
+	group, err := config.Store.Load(config.groupTokenKey(5))
+	if err != nil || group == nil || group.AccessToken != "group" || group.Kind != GroupToken || group.GroupScope != GroupScopeManage {
+		t.Errorf("got group %+v, %v", group, err)
+	}
+}
+
+func TestOAuthHandler(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		cookie  string
+		onToken bool
+		status  int
+		err     string
+	}{
+		{"ok", "code=abc&state=s1", "s1", true, http.StatusOK, ""},
+		{"default response", "code=abc&state=s1", "s1", false, http.StatusOK, ""},
+		{"no cookie", "code=abc&state=s1", "", true, http.StatusBadRequest, "invalid_state"},
+		{"other state", "code=abc&state=s1", "s2", true, http.StatusBadRequest, "invalid_state"},
+		{"no state", "code=abc", "s1", true, http.StatusBadRequest, "invalid_state"},
+		{"denied", "error=access_denied&error_description=denied&state=s1", "s1", true, http.StatusBadRequest, "access_denied"},
+	}
+	for _, tt := range tests {
+		var query url.Values
+		srv := oauthServer(`{"access_token": "user", "user_id": 7}`, &query)
+
+		var got *OAuthToken
+		var gotErr error
+		h := &OAuthHandler{Config: oauthConfig(srv)}
+		if tt.onToken {
+			h.OnToken = func(w http.ResponseWriter, r *http.Request, token *OAuthToken) {
+				got = token
+			}
+		}
+		if tt.err != "" {
+			h.OnError = func(w http.ResponseWriter, r *http.Request, err error) {
+				gotErr = err
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		}
+
+		r := httptest.NewRequest("GET", "/vk?"+tt.query, nil)
+		if tt.cookie != "" {
+			r.AddCookie(&http.Cookie{Name: oauthStateCookie, Value: tt.cookie})
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		srv.Close()
+
+		if w.Code != tt.status {
+			t.Errorf("%s: got status %d, want %d", tt.name, w.Code, tt.status)
+		}
+		if tt.err != "" {
+			if oauthErr, ok := gotErr.(*OAuthError); !ok || oauthErr.Code != tt.err {
+				t.Errorf("%s: got error %v, want %s", tt.name, gotErr, tt.err)
+			}
+			if query != nil {
+				t.Errorf("%s: code is exchanged", tt.name)
+			}
+			continue
+		}
+		if query.Get("code") != "abc" {
+			t.Errorf("%s: got code %q", tt.name, query.Get("code"))
+		}
+		if tt.onToken && (got == nil || got.AccessToken != "user") {
+			t.Errorf("%s: got token %+v", tt.name, got)
+		}
+	}
+}
+
+func TestOAuthHandlerRedirect(t *testing.T) {
+	h := &OAuthHandler{Config: &OAuthConfig{ClientID: "1", RedirectURI: "https://example.com/vk", Scope: ScopeWall}}
+	w := httptest.NewRecorder()
+	h.Redirect(w, httptest.NewRequest("GET", "/login", nil))
+
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 || cookies[0].Name != oauthStateCookie || cookies[0].Value == "" {
+		t.Fatalf("got cookies %v", cookies)
+	}
+	location, err := url.Parse(w.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if location.Query().Get("state") != cookies[0].Value || location.Query().Get("scope") != "wall" {
+		t.Errorf("got location %s", location)
+	}
+}
@@ -6,12 +6,15 @@ type UserObject struct {
 	ID         int    `json:"id"`
 	FirstName  string `json:"first_name"`
 	LastName   string `json:"last_name"`
-	Sex        int    `json:"sex"`
+	Sex        Sex    `json:"sex"`
 	Nickname   string `json:"nickname"`
 	MaidenName string `json:"maiden_name"`
 	Domain     string `json:"domain"`
 	ScreenName string `json:"screen_name"`
 	Bdate      string `json:"bdate"`
+	// profile is private
+	IsClosed        BoolInt `json:"is_closed"`
+	CanAccessClosed BoolInt `json:"can_access_closed"`
 	// one of: moderator, editor, administrator, creator
 	Role string `json:"role"`
 	City struct {
@@ -22,46 +25,50 @@ type UserObject struct {
 		ID    int    `json:"id"`
 		Title string `json:"title"`
 	} `json:"country"`
-	Photo50                string `json:"photo_50"`
-	Photo100               string `json:"photo_100"`
-	Photo200               string `json:"photo_200"`
-	PhotoMax               string `json:"photo_max"`
-	Photo200Orig           string `json:"photo_200_orig"`
-	Photo400Orig           string `json:"photo_400_orig"`
-	PhotoMaxOrig           string `json:"photo_max_orig"`
-	PhotoID                string `json:"photo_id"`
-	HasPhoto               int    `json:"has_photo"`
-	HasMobile              int    `json:"has_mobile"`
-	IsFriend               int    `json:"is_friend"`
-	FriendStatus           int    `json:"friend_status"`
-	Online                 int    `json:"online"`
-	WallComments           int    `json:"wall_comments"`
-	CanPost                int    `json:"can_post"`
-	CanSeeAllPosts         int    `json:"can_see_all_posts"`
-	CanSeeAudio            int    `json:"can_see_audio"`
-	CanWritePrivateMessage int    `json:"can_write_private_message"`
-	CanSendFriendRequest   int    `json:"can_send_friend_request"`
-	MobilePhone            string `json:"mobile_phone"`
-	HomePhone              string `json:"home_phone"`
-	Site                   string `json:"site"`
-	Status                 string `json:"status"`
-	LastSeen               struct {
-		Time     int `json:"time"`
-		Platform int `json:"platform"`
+	Photo50                string  `json:"photo_50"`
+	Photo100               string  `json:"photo_100"`
+	Photo200               string  `json:"photo_200"`
+	PhotoMax               string  `json:"photo_max"`
+	Photo200Orig           string  `json:"photo_200_orig"`
+	Photo400Orig           string  `json:"photo_400_orig"`
+	PhotoMaxOrig           string  `json:"photo_max_orig"`
+	PhotoID                string  `json:"photo_id"`
+	HasPhoto               BoolInt `json:"has_photo"`
+	HasMobile              BoolInt `json:"has_mobile"`
+	IsFriend               BoolInt `json:"is_friend"`
+	FriendStatus           int     `json:"friend_status"`
+	Online                 BoolInt `json:"online"`
+	WallComments           BoolInt `json:"wall_comments"`
+	CanPost                BoolInt `json:"can_post"`
+	CanSeeAllPosts         BoolInt `json:"can_see_all_posts"`
+	CanSeeAudio            BoolInt `json:"can_see_audio"`
+	CanWritePrivateMessage BoolInt `json:"can_write_private_message"`
+	CanSendFriendRequest   BoolInt `json:"can_send_friend_request"`
+	// filled with the contacts field
+	UserContacts
+	// filled with the connections field
+	UserConnections
+	// filled with the education field
+	UserEducation
+	Site     string `json:"site"`
+	Status   string `json:"status"`
+	LastSeen struct {
+		Time     UnixTime `json:"time"`
+		Platform Platform `json:"platform"`
 	} `json:"last_seen"`
 	CropPhoto struct {
 		Photo struct {
-			ID       int    `json:"id"`
-			AlbumID  int    `json:"album_id"`
-			OwnerID  int    `json:"owner_id"`
-			Photo75  string `json:"photo_75"`
-			Photo130 string `json:"photo_130"`
-			Photo604 string `json:"photo_604"`
-			Width    int    `json:"width"`
-			Height   int    `json:"height"`
-			Text     string `json:"text"`
-			Date     int    `json:"date"`
-			PostID   int    `json:"post_id"`
+			ID       int      `json:"id"`
+			AlbumID  int      `json:"album_id"`
+			OwnerID  int      `json:"owner_id"`
+			Photo75  string   `json:"photo_75"`
+			Photo130 string   `json:"photo_130"`
+			Photo604 string   `json:"photo_604"`
+			Width    int      `json:"width"`
+			Height   int      `json:"height"`
+			Text     string   `json:"text"`
+			Date     UnixTime `json:"date"`
+			PostID   int      `json:"post_id"`
 		} `json:"photo"`
 		Crop struct {
 			X  float64 `json:"x"`
@@ -76,43 +83,184 @@ type UserObject struct {
 			Y2 int `json:"y2"`
 		} `json:"rect"`
 	} `json:"crop_photo"`
-	Verified         int           `json:"verified"`
-	FollowersCount   int           `json:"followers_count"`
-	Blacklisted      int           `json:"blacklisted"`
-	BlacklistedByMe  int           `json:"blacklisted_by_me"`
-	IsFavorite       int           `json:"is_favorite"`
-	IsHiddenFromFeed int           `json:"is_hidden_from_feed"`
-	CommonCount      int           `json:"common_count"`
-	Career           []interface{} `json:"career"`
-	Military         []interface{} `json:"military"`
-	University       int           `json:"university"`
-	UniversityName   string        `json:"university_name"`
-	Faculty          int           `json:"faculty"`
-	FacultyName      string        `json:"faculty_name"`
-	Graduation       int           `json:"graduation"`
-	HomeTown         string        `json:"home_town"`
-	Relation         int           `json:"relation"`
-	Personal         struct {
-		Religion   string `json:"religion"`
-		InspiredBy string `json:"inspired_by"`
-		PeopleMain int    `json:"people_main"`
-		LifeMain   int    `json:"life_main"`
-		Smoking    int    `json:"smoking"`
-		Alcohol    int    `json:"alcohol"`
-	} `json:"personal"`
-	Interests    string        `json:"interests"`
-	Music        string        `json:"music"`
-	Activities   string        `json:"activities"`
-	Movies       string        `json:"movies"`
-	Tv           string        `json:"tv"`
-	Books        string        `json:"books"`
-	Games        string        `json:"games"`
-	Universities []interface{} `json:"universities"`
-	Schools      []interface{} `json:"schools"`
-	About        string        `json:"about"`
-	Relatives    []interface{} `json:"relatives"`
-	Quotes       string        `json:"quotes"`
-	Deactivated  string        `json:"deactivated"`
+	Verified         BoolInt        `json:"verified"`
+	FollowersCount   int            `json:"followers_count"`
+	Blacklisted      BoolInt        `json:"blacklisted"`
+	BlacklistedByMe  BoolInt        `json:"blacklisted_by_me"`
+	IsFavorite       BoolInt        `json:"is_favorite"`
+	IsHiddenFromFeed BoolInt        `json:"is_hidden_from_feed"`
+	CommonCount      int            `json:"common_count"`
+	Career           []UserCareer   `json:"career"`
+	Military         []UserMilitary `json:"military"`
+	HomeTown         string         `json:"home_town"`
+	Relation         Relation       `json:"relation"`
+	// partner of the user, only ID and name are filled
+	RelationPartner *UserObject      `json:"relation_partner"`
+	Personal        *UserPersonal    `json:"personal"`
+	Occupation      *UserOccupation  `json:"occupation"`
+	Counters        *UserCounters    `json:"counters"`
+	Exports         *UserExports     `json:"exports"`
+	Interests       string           `json:"interests"`
+	Music           string           `json:"music"`
+	Activities      string           `json:"activities"`
+	Movies          string           `json:"movies"`
+	Tv              string           `json:"tv"`
+	Books           string           `json:"books"`
+	Games           string           `json:"games"`
+	Universities    []UserUniversity `json:"universities"`
+	Schools         []UserSchool     `json:"schools"`
+	About           string           `json:"about"`
+	Relatives       []UserRelative   `json:"relatives"`
+	Quotes          string           `json:"quotes"`
+	Deactivated     Deactivated      `json:"deactivated"`
+}
+
+// An UserContacts contains phone numbers of the user.
+// https://vk.com/dev/objects/user
+type UserContacts struct {
+	MobilePhone string `json:"mobile_phone"`
+	HomePhone   string `json:"home_phone"`
+}
+
+// An UserConnections contains accounts of the user in other services.
+// https://vk.com/dev/objects/user
+type UserConnections struct {
+	Skype       string `json:"skype"`
+	Facebook    string `json:"facebook"`
+	Twitter     string `json:"twitter"`
+	Livejournal string `json:"livejournal"`
+	Instagram   string `json:"instagram"`
+}
+
+// An UserEducation contains the main higher education of the user.
+// https://vk.com/dev/objects/user
+type UserEducation struct {
+	University      int    `json:"university"`
+	UniversityName  string `json:"university_name"`
+	Faculty         int    `json:"faculty"`
+	FacultyName     string `json:"faculty_name"`
+	Graduation      int    `json:"graduation"`
+	EducationForm   string `json:"education_form"`
+	EducationStatus string `json:"education_status"`
+}
+
+// An UserCareer contains information about job of the user.
+// Either GroupID or Company is set, either CityID or CityName.
+// https://vk.com/dev/objects/user
+type UserCareer struct {
+	GroupID   int    `json:"group_id"`
+	Company   string `json:"company"`
+	CountryID int    `json:"country_id"`
+	CityID    int    `json:"city_id"`
+	CityName  string `json:"city_name"`
+	From      int    `json:"from"`
+	Until     int    `json:"until"`
+	Position  string `json:"position"`
+}
+
+// An UserMilitary contains information about military service of the user.
+// https://vk.com/dev/objects/user
+type UserMilitary struct {
+	Unit      string `json:"unit"`
+	UnitID    int    `json:"unit_id"`
+	CountryID int    `json:"country_id"`
+	From      int    `json:"from"`
+	Until     int    `json:"until"`
+}
+
+// An UserUniversity contains information about university of the user.
+// https://vk.com/dev/objects/user
+type UserUniversity struct {
+	ID              int    `json:"id"`
+	Country         int    `json:"country"`
+	City            int    `json:"city"`
+	Name            string `json:"name"`
+	Faculty         int    `json:"faculty"`
+	FacultyName     string `json:"faculty_name"`
+	Chair           int    `json:"chair"`
+	ChairName       string `json:"chair_name"`
+	Graduation      int    `json:"graduation"`
+	EducationForm   string `json:"education_form"`
+	EducationStatus string `json:"education_status"`
+}
+
+// An UserSchool contains information about school of the user.
+// https://vk.com/dev/objects/user
+type UserSchool struct {
+	// VK sends ID of the school as a string.
+	ID            string `json:"id"`
+	Country       int    `json:"country"`
+	City          int    `json:"city"`
+	Name          string `json:"name"`
+	YearFrom      int    `json:"year_from"`
+	YearTo        int    `json:"year_to"`
+	YearGraduated int    `json:"year_graduated"`
+	Class         string `json:"class"`
+	Speciality    string `json:"speciality"`
+	Type          int    `json:"type"`
+	TypeStr       string `json:"type_str"`
+}
+
+// An UserRelative contains information about relative of the user.
+// ID is set if the relative has a page, Name otherwise.
+// https://vk.com/dev/objects/user
+type UserRelative struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// one of: child, sibling, parent, grandparent, grandchild
+	Type string `json:"type"`
+}
+
+// An UserPersonal contains information from the "Personal views" section.
+// https://vk.com/dev/objects/user
+type UserPersonal struct {
+	Political  int      `json:"political"`
+	Langs      []string `json:"langs"`
+	Religion   string   `json:"religion"`
+	InspiredBy string   `json:"inspired_by"`
+	PeopleMain int      `json:"people_main"`
+	LifeMain   int      `json:"life_main"`
+	Smoking    int      `json:"smoking"`
+	Alcohol    int      `json:"alcohol"`
+}
+
+// An UserOccupation contains current occupation of the user.
+// https://vk.com/dev/objects/user
+type UserOccupation struct {
+	// one of: work, school, university
+	Type string `json:"type"`
+	// ID of the community, school or university
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// An UserCounters contains counters of the user.
+// Returned only when a single user is requested.
+// https://vk.com/dev/objects/user
+type UserCounters struct {
+	Albums        int `json:"albums"`
+	Videos        int `json:"videos"`
+	Audios        int `json:"audios"`
+	Photos        int `json:"photos"`
+	Notes         int `json:"notes"`
+	Friends       int `json:"friends"`
+	Groups        int `json:"groups"`
+	OnlineFriends int `json:"online_friends"`
+	MutualFriends int `json:"mutual_friends"`
+	UserVideos    int `json:"user_videos"`
+	UserPhotos    int `json:"user_photos"`
+	Followers     int `json:"followers"`
+	Pages         int `json:"pages"`
+	Subscriptions int `json:"subscriptions"`
+}
+
+// An UserExports contains services the user exports posts to.
+// https://vk.com/dev/objects/user
+type UserExports struct {
+	Twitter     BoolInt `json:"twitter"`
+	Facebook    BoolInt `json:"facebook"`
+	Livejournal BoolInt `json:"livejournal"`
+	Instagram   BoolInt `json:"instagram"`
 }
 
 // A PhotoObject contains information about photo.
@@ -122,26 +270,22 @@ type PhotoObject struct {
 	AlbumID int `json:"album_id"`
 	OwnerID int `json:"owner_id"`
 	UserID  int `json:"user_id"`
-	Sizes   []struct {
-		Src    string `json:"src"`
-		Width  int    `json:"width"`
-		Height int    `json:"height"`
-		Type   string `json:"type"`
-	} `json:"sizes"`
-	Text      string `json:"text"`
-	Date      int    `json:"date"`
-	PostID    int    `json:"post_id"`
-	Width     int    `json:"width"`
-	Height    int    `json:"height"`
-	Photo75   string `json:"photo_75"`
-	Photo130  string `json:"photo_130"`
-	Photo604  string `json:"photo_604"`
-	Photo807  string `json:"photo_807"`
-	Photo1280 string `json:"photo_1280"`
-	Photo2560 string `json:"photo_2560"`
+	// filled if photo_sizes is set, see also AllSizes
+	Sizes     []PhotoSize `json:"sizes"`
+	Text      string      `json:"text"`
+	Date      UnixTime    `json:"date"`
+	PostID    int         `json:"post_id"`
+	Width     int         `json:"width"`
+	Height    int         `json:"height"`
+	Photo75   string      `json:"photo_75"`
+	Photo130  string      `json:"photo_130"`
+	Photo604  string      `json:"photo_604"`
+	Photo807  string      `json:"photo_807"`
+	Photo1280 string      `json:"photo_1280"`
+	Photo2560 string      `json:"photo_2560"`
 	Likes     struct {
-		UserLikes int `json:"user_likes"`
-		Count     int `json:"count"`
+		UserLikes BoolInt `json:"user_likes"`
+		Count     int     `json:"count"`
 	} `json:"likes"`
 	Reposts struct {
 		Count int `json:"count"`
@@ -149,48 +293,158 @@ type PhotoObject struct {
 	Comments struct {
 		Count int `json:"count"`
 	} `json:"comments"`
-	CanComment int `json:"can_comment"`
+	CanComment BoolInt `json:"can_comment"`
 	Tags       struct {
 		Count int `json:"count"`
 	} `json:"tags"`
 }
 
+// A PhotoAlbumObject contains information about photo album.
+// https://vk.com/dev/objects/photo_album
+type PhotoAlbumObject struct {
+	ID          int      `json:"id"`
+	ThumbID     int      `json:"thumb_id"`
+	OwnerID     int      `json:"owner_id"`
+	Title       string   `json:"title"`
+	Description string   `json:"description"`
+	Created     UnixTime `json:"created"`
+	Updated     UnixTime `json:"updated"`
+	// number of photos
+	Size int `json:"size"`
+	// filled if need_covers is set
+	ThumbSrc string `json:"thumb_src"`
+	// filled if need_covers and photo_sizes are set
+	Sizes              []PhotoSize `json:"sizes"`
+	CanUpload          BoolInt     `json:"can_upload"`
+	UploadByAdminsOnly BoolInt     `json:"upload_by_admins_only"`
+	CommentsDisabled   BoolInt     `json:"comments_disabled"`
+}
+
 // A VideoObject contains information about video.
 // https://vk.com/dev/objects/video
 type VideoObject struct {
-	ID          int    `json:"id"`
-	OwnerID     int    `json:"owner_id"`
-	Title       string `json:"title"`
-	Duration    int    `json:"duration"`
-	Description string `json:"description"`
-	Date        int    `json:"date"`
-	Comments    int    `json:"comments"`
-	Views       int    `json:"views"`
-	Width       int    `json:"width"`
-	Height      int    `json:"height"`
-	Photo130    string `json:"photo_130"`
-	Photo320    string `json:"photo_320"`
-	Photo800    string `json:"photo_800"`
-	AddingDate  int    `json:"adding_date"`
-	Files       struct {
-		Mp4240 string `json:"mp4_240"`
-		Mp4360 string `json:"mp4_360"`
-		Mp4480 string `json:"mp4_480"`
-		Mp4720 string `json:"mp4_720"`
-	} `json:"files"`
-	Player     string `json:"player"`
-	CanAdd     int    `json:"can_add"`
-	CanComment int    `json:"can_comment"`
-	CanRepost  int    `json:"can_repost"`
+	ID          int      `json:"id"`
+	OwnerID     int      `json:"owner_id"`
+	Title       string   `json:"title"`
+	Duration    int      `json:"duration"`
+	Description string   `json:"description"`
+	Date        UnixTime `json:"date"`
+	Comments    int      `json:"comments"`
+	Views       int      `json:"views"`
+	LocalViews  int      `json:"local_views"`
+	Width       int      `json:"width"`
+	Height      int      `json:"height"`
+	Photo130    string   `json:"photo_130"`
+	Photo320    string   `json:"photo_320"`
+	Photo800    string   `json:"photo_800"`
+	Photo1280   string   `json:"photo_1280"`
+	// preview images of different sizes
+	Image      []VideoImage `json:"image"`
+	FirstFrame []VideoImage `json:"first_frame"`
+	AddingDate UnixTime     `json:"adding_date"`
+	AccessKey  string       `json:"access_key"`
+	// one of: video, music_video, movie
+	Type string `json:"type"`
+	// name of the external platform, e.g. YouTube
+	Platform string `json:"platform"`
+	// links to files, only for the owner or with the video scope
+	Files   VideoFiles `json:"files"`
+	Player  string     `json:"player"`
+	CanEdit BoolInt    `json:"can_edit"`
+	CanAdd  BoolInt    `json:"can_add"`
+	// the video is in the list of the current user
+	Added         BoolInt `json:"added"`
+	IsPrivate     BoolInt `json:"is_private"`
+	IsFavorite    BoolInt `json:"is_favorite"`
+	CanComment    BoolInt `json:"can_comment"`
+	CanRepost     BoolInt `json:"can_repost"`
+	CanLike       BoolInt `json:"can_like"`
+	CanAddToFaves BoolInt `json:"can_add_to_faves"`
+	CanSubscribe  BoolInt `json:"can_subscribe"`
+	IsSubscribed  BoolInt `json:"is_subscribed"`
+	// the video is being processed after upload
+	Processing BoolInt `json:"processing"`
+	Converting BoolInt `json:"converting"`
+	// live broadcast
+	Live     BoolInt `json:"live"`
+	Upcoming BoolInt `json:"upcoming"`
+	// one of: waiting, started, finished, failed, upcoming
+	LiveStatus    string   `json:"live_status"`
+	LiveStartTime UnixTime `json:"live_start_time"`
+	// number of viewers of the live broadcast
+	Spectators int `json:"spectators"`
 	Likes      struct {
-		UserLikes int `json:"user_likes"`
-		Count     int `json:"count"`
+		UserLikes BoolInt `json:"user_likes"`
+		Count     int     `json:"count"`
 	} `json:"likes"`
 	Reposts struct {
-		Count        int `json:"count"`
-		UserReposted int `json:"user_reposted"`
+		Count        int     `json:"count"`
+		UserReposted BoolInt `json:"user_reposted"`
 	} `json:"reposts"`
-	Repeat int `json:"repeat"`
+	Repeat BoolInt `json:"repeat"`
+	// set if the video can't be played for everyone
+	Restriction *VideoRestriction `json:"restriction"`
+	// set if the video can't be played in the country of the user
+	ContentRestricted        BoolInt `json:"content_restricted"`
+	ContentRestrictedMessage string  `json:"content_restricted_message"`
+}
+
+// A VideoImage describes a preview image of the video.
+// https://vk.com/dev/objects/video
+type VideoImage struct {
+	URL         string  `json:"url"`
+	Width       int     `json:"width"`
+	Height      int     `json:"height"`
+	WithPadding BoolInt `json:"with_padding"`
+}
+
+// A VideoFiles contains links to files of the video.
+// Only links to available qualities are filled.
+// https://vk.com/dev/objects/video
+type VideoFiles struct {
+	Mp4144  string `json:"mp4_144"`
+	Mp4240  string `json:"mp4_240"`
+	Mp4360  string `json:"mp4_360"`
+	Mp4480  string `json:"mp4_480"`
+	Mp4720  string `json:"mp4_720"`
+	Mp41080 string `json:"mp4_1080"`
+	Mp41440 string `json:"mp4_1440"`
+	Mp42160 string `json:"mp4_2160"`
+	HLS     string `json:"hls"`
+	DashSep string `json:"dash_sep"`
+	// link to the external video, e.g. on YouTube
+	External string `json:"external"`
+	// playlist of the live broadcast
+	HLSLive string `json:"hls_live"`
+	// preview of the video on hover
+	FLV320 string `json:"flv_320"`
+}
+
+// A VideoRestriction describes why the video can't be played.
+// https://vk.com/dev/objects/video
+type VideoRestriction struct {
+	Title       string  `json:"title"`
+	Text        string  `json:"text"`
+	AlwaysShown BoolInt `json:"always_shown"`
+	Blur        BoolInt `json:"blur"`
+	CanPlay     BoolInt `json:"can_play"`
+	CanPreview  BoolInt `json:"can_preview"`
+}
+
+// A VideoAlbumObject contains information about video album.
+// https://vk.com/dev/video.getAlbums
+type VideoAlbumObject struct {
+	ID      int    `json:"id"`
+	OwnerID int    `json:"owner_id"`
+	Title   string `json:"title"`
+	// number of videos
+	Count       int          `json:"count"`
+	UpdatedTime UnixTime     `json:"updated_time"`
+	Image       []VideoImage `json:"image"`
+	Photo160    string       `json:"photo_160"`
+	Photo320    string       `json:"photo_320"`
+	// album "Added" or "Uploaded"
+	IsSystem BoolInt `json:"is_system"`
 }
 
 const (
@@ -201,20 +455,93 @@ const (
 )
 
 type GroupObject struct {
-	Id         int    `json:"id"`
-	Name       string `json:"name"`
-	ScreenName string `json:"screen_name"`
-	IsClosed   int    `json:"is_closed"`
-	Type       string `json:"type"`
-	Photo50    string `json:"photo_50"`
-	Photo100   string `json:"photo_100"`
-	Photo200   string `json:"photo_200"`
+	Id         int         `json:"id"`
+	Name       string      `json:"name"`
+	ScreenName string      `json:"screen_name"`
+	IsClosed   GroupAccess `json:"is_closed"`
+	Type       GroupType   `json:"type"`
+	Photo50    string      `json:"photo_50"`
+	Photo100   string      `json:"photo_100"`
+	Photo200   string      `json:"photo_200"`
 
-	IsAdmin    int `json:"is_admin"`
-	AdminLevel int `json:"admin_level"`
-	IsMember   int `json:"is_member"`
+	IsAdmin    BoolInt `json:"is_admin"`
+	AdminLevel int     `json:"admin_level"`
+	IsMember   BoolInt `json:"is_member"`
 
 	MembersCount int `json:"members_count,omitempty"`
+
+	Deactivated Deactivated `json:"deactivated"`
+	Activity    string      `json:"activity"`
+	Description string      `json:"description"`
+	Status      string      `json:"status"`
+	Site        string      `json:"site"`
+	Verified    BoolInt     `json:"verified"`
+	CanPost     BoolInt     `json:"can_post"`
+	CanMessage  BoolInt     `json:"can_message"`
+	IsFavorite  BoolInt     `json:"is_favorite"`
+	FixedPost   int         `json:"fixed_post"`
+	MainAlbumID int         `json:"main_album_id"`
+	StartDate   UnixTime    `json:"start_date"`
+	FinishDate  UnixTime    `json:"finish_date"`
+	City        struct {
+		ID    int    `json:"id"`
+		Title string `json:"title"`
+	} `json:"city"`
+	Country struct {
+		ID    int    `json:"id"`
+		Title string `json:"title"`
+	} `json:"country"`
+	Counters  GroupCounters  `json:"counters"`
+	Contacts  []GroupContact `json:"contacts"`
+	Links     []GroupLink    `json:"links"`
+	Addresses struct {
+		IsEnabled     bool `json:"is_enabled"`
+		MainAddressID int  `json:"main_address_id"`
+	} `json:"addresses"`
+	Cover GroupCover `json:"cover"`
+}
+
+// A GroupCounters contains counters of the community.
+// https://vk.com/dev/objects/group
+type GroupCounters struct {
+	Photos int `json:"photos"`
+	Albums int `json:"albums"`
+	Audios int `json:"audios"`
+	Videos int `json:"videos"`
+	Topics int `json:"topics"`
+	Docs   int `json:"docs"`
+	Market int `json:"market"`
+}
+
+// A GroupContact contains information about contact person of the community.
+// https://vk.com/dev/objects/group
+type GroupContact struct {
+	UserID int    `json:"user_id"`
+	Desc   string `json:"desc"`
+	Phone  string `json:"phone"`
+	Email  string `json:"email"`
+}
+
+// A GroupLink contains information about link of the community.
+// https://vk.com/dev/objects/group
+type GroupLink struct {
+	ID       int    `json:"id"`
+	URL      string `json:"url"`
+	Name     string `json:"name"`
+	Desc     string `json:"desc"`
+	Photo50  string `json:"photo_50"`
+	Photo100 string `json:"photo_100"`
+}
+
+// A GroupCover contains information about cover of the community.
+// https://vk.com/dev/objects/group
+type GroupCover struct {
+	Enabled int `json:"enabled"`
+	Images  []struct {
+		URL    string `json:"url"`
+		Width  int    `json:"width"`
+		Height int    `json:"height"`
+	} `json:"images"`
 }
 
 func (g *GroupObject) IsModerator() bool {
@@ -228,3 +555,330 @@ func (g *GroupObject) IsEditor() bool {
 func (g *GroupObject) IsAdministrator() bool {
 	return g.AdminLevel == AdminLevelAdministrator
 }
+
+// A CommentObject contains information about comment
+// on a post, photo, video, product or in a topic.
+// https://vk.com/dev/objects/comment
+type CommentObject struct {
+	ID     int      `json:"id"`
+	FromID int      `json:"from_id"`
+	Date   UnixTime `json:"date"`
+	Text   string   `json:"text"`
+	// filled in callback events and wall.getComment
+	OwnerID int `json:"owner_id"`
+	PostID  int `json:"post_id"`
+	// ID of the user or community the comment is a reply to
+	ReplyToUser    int                `json:"reply_to_user"`
+	ReplyToComment int                `json:"reply_to_comment"`
+	Attachments    []AttachmentObject `json:"attachments"`
+	// IDs of parent comments, if it's a reply in the thread
+	ParentsStack []int `json:"parents_stack"`
+	// filled if need_likes is set
+	Likes *struct {
+		Count     int     `json:"count"`
+		UserLikes BoolInt `json:"user_likes"`
+		CanLike   BoolInt `json:"can_like"`
+	} `json:"likes"`
+	// replies to the comment, wall comments only
+	Thread *struct {
+		Count           int             `json:"count"`
+		Items           []CommentObject `json:"items"`
+		CanPost         BoolInt         `json:"can_post"`
+		ShowReplyButton BoolInt         `json:"show_reply_button"`
+	} `json:"thread"`
+	Deleted BoolInt `json:"deleted"`
+	// filled by Wall.GetComments, see ReactionSet
+	ReactionSetID string           `json:"reaction_set_id"`
+	Reactions     *ReactionsObject `json:"reactions"`
+}
+
+// A PostObject contains information about post on the wall.
+// https://vk.com/dev/objects/post
+type PostObject struct {
+	ID      int `json:"id"`
+	OwnerID int `json:"owner_id"`
+	FromID  int `json:"from_id"`
+	// admin who posted on behalf of the community
+	CreatedBy    int      `json:"created_by"`
+	Date         UnixTime `json:"date"`
+	Text         string   `json:"text"`
+	ReplyOwnerID int      `json:"reply_owner_id"`
+	ReplyPostID  int      `json:"reply_post_id"`
+	FriendsOnly  BoolInt  `json:"friends_only"`
+	Comments     struct {
+		Count   int     `json:"count"`
+		CanPost BoolInt `json:"can_post"`
+	} `json:"comments"`
+	Likes struct {
+		Count      int     `json:"count"`
+		UserLikes  BoolInt `json:"user_likes"`
+		CanLike    BoolInt `json:"can_like"`
+		CanPublish BoolInt `json:"can_publish"`
+	} `json:"likes"`
+	Reposts struct {
+		Count        int     `json:"count"`
+		UserReposted BoolInt `json:"user_reposted"`
+	} `json:"reposts"`
+	Views struct {
+		Count int `json:"count"`
+	} `json:"views"`
+	// one of: post, copy, reply, postpone, suggest
+	PostType    string             `json:"post_type"`
+	Attachments []AttachmentObject `json:"attachments"`
+	SignerID    int                `json:"signer_id"`
+	// reposted posts, the original is the last one
+	CopyHistory []PostObject `json:"copy_history"`
+	IsPinned    BoolInt      `json:"is_pinned"`
+	MarkedAsAds BoolInt      `json:"marked_as_ads"`
+	// filled by Wall.Get and Wall.GetById, see ReactionSet
+	ReactionSetID string           `json:"reaction_set_id"`
+	Reactions     *ReactionsObject `json:"reactions"`
+}
+
+// A ReactionsObject describes reactions on the post or comment.
+type ReactionsObject struct {
+	// count of all reactions
+	Count int `json:"count"`
+	// reaction of the current user, nil if there is none
+	UserReaction *int            `json:"user_reaction"`
+	Items        []ReactionCount `json:"items"`
+}
+
+// A ReactionCount describes how many times the reaction is left.
+type ReactionCount struct {
+	// ID of the reaction in the set
+	ID    int `json:"id"`
+	Count int `json:"count"`
+}
+
+// ByID returns the count of the reaction.
+func (r *ReactionsObject) ByID(id int) int {
+	for _, item := range r.Items {
+		if item.ID == id {
+			return item.Count
+		}
+	}
+	return 0
+}
+
+// A ReactionSet describes reactions available on posts
+// and comments with its ReactionSetID.
+type ReactionSet struct {
+	ID    string     `json:"id"`
+	Items []Reaction `json:"items"`
+}
+
+// A Reaction describes a reaction of the set.
+// Zero ID is the usual like.
+type Reaction struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+	Asset struct {
+		AnimationURL string `json:"animation_url"`
+		Images       []struct {
+			URL    string `json:"url"`
+			Width  int    `json:"width"`
+			Height int    `json:"height"`
+		} `json:"images"`
+	} `json:"asset"`
+}
+
+// An AttachmentObject contains information about attachment
+// of a post or comment. Only the field named by Type is filled.
+// https://vk.com/dev/objects/attachments_w
+type AttachmentObject struct {
+	// one of: photo, video, audio, doc, link, market, sticker and so on
+	Type  string       `json:"type"`
+	Photo *PhotoObject `json:"photo"`
+	Video *VideoObject `json:"video"`
+	Link  *LinkObject  `json:"link"`
+}
+
+// A LinkObject contains information about attached link.
+// https://vk.com/dev/objects/link
+type LinkObject struct {
+	URL         string       `json:"url"`
+	Title       string       `json:"title"`
+	Caption     string       `json:"caption"`
+	Description string       `json:"description"`
+	Photo       *PhotoObject `json:"photo"`
+}
+
+// A PhotoTagObject contains information about user tagged on photo.
+// Coordinates are percents of the photo size.
+// https://vk.com/dev/photos.getTags
+type PhotoTagObject struct {
+	ID         int      `json:"id"`
+	UserID     int      `json:"user_id"`
+	PlacerID   int      `json:"placer_id"`
+	TaggedName string   `json:"tagged_name"`
+	Date       UnixTime `json:"date"`
+	X          float64  `json:"x"`
+	Y          float64  `json:"y"`
+	X2         float64  `json:"x2"`
+	Y2         float64  `json:"y2"`
+	Viewed     BoolInt  `json:"viewed"`
+}
+
+// A TopicObject contains information about topic
+// on a community's discussion board.
+// https://vk.com/dev/board.getTopics
+type TopicObject struct {
+	ID        int      `json:"id"`
+	Title     string   `json:"title"`
+	Created   UnixTime `json:"created"`
+	CreatedBy int      `json:"created_by"`
+	Updated   UnixTime `json:"updated"`
+	UpdatedBy int      `json:"updated_by"`
+	IsClosed  BoolInt  `json:"is_closed"`
+	IsFixed   BoolInt  `json:"is_fixed"`
+	// count of comments
+	Comments int `json:"comments"`
+	// filled if preview is requested
+	FirstComment string `json:"first_comment"`
+	LastComment  string `json:"last_comment"`
+}
+
+// A TopicCommentObject contains information about comment in the topic.
+// https://vk.com/dev/board.getComments
+type TopicCommentObject struct {
+	ID          int                `json:"id"`
+	FromID      int                `json:"from_id"`
+	Date        UnixTime           `json:"date"`
+	Text        string             `json:"text"`
+	Attachments []AttachmentObject `json:"attachments"`
+	// filled if need_likes is set
+	Likes *struct {
+		Count     int     `json:"count"`
+		UserLikes BoolInt `json:"user_likes"`
+		CanLike   BoolInt `json:"can_like"`
+	} `json:"likes"`
+	// offset of the comment in the topic, filled with start_comment_id
+	RealOffset int `json:"real_offset"`
+}
+
+// A MarketItemObject contains information about product.
+// https://vk.com/dev/objects/market_item
+type MarketItemObject struct {
+	ID          int               `json:"id"`
+	OwnerID     int               `json:"owner_id"`
+	Title       string            `json:"title"`
+	Description string            `json:"description"`
+	Price       MarketPrice       `json:"price"`
+	Dimensions  *MarketDimensions `json:"dimensions"`
+	// weight in grams
+	Weight     int            `json:"weight"`
+	Category   MarketCategory `json:"category"`
+	ThumbPhoto string         `json:"thumb_photo"`
+	Date       UnixTime       `json:"date"`
+	// 0 — available, 1 — deleted, 2 — unavailable
+	Availability int     `json:"availability"`
+	IsFavorite   BoolInt `json:"is_favorite"`
+	SKU          string  `json:"sku"`
+	URL          string  `json:"url"`
+	ButtonTitle  string  `json:"button_title"`
+	ViewsCount   int     `json:"views_count"`
+	AlbumsIDs    []int   `json:"albums_ids"`
+	// filled if extended is set
+	Photos     []PhotoObject `json:"photos"`
+	CanComment BoolInt       `json:"can_comment"`
+	CanRepost  BoolInt       `json:"can_repost"`
+	Likes      struct {
+		UserLikes BoolInt `json:"user_likes"`
+		Count     int     `json:"count"`
+	} `json:"likes"`
+}
+
+// A MarketPrice contains price of the product.
+// Amounts are in hundredths of the currency, e.g. "12300" for 123.00.
+// https://vk.com/dev/objects/market_item
+type MarketPrice struct {
+	Amount    string `json:"amount"`
+	OldAmount string `json:"old_amount"`
+	Currency  struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"currency"`
+	// formatted price, e.g. "123 rub."
+	Text string `json:"text"`
+}
+
+// A MarketDimensions contains dimensions of the product in millimeters.
+// https://vk.com/dev/objects/market_item
+type MarketDimensions struct {
+	Width  int `json:"width"`
+	Height int `json:"height"`
+	Length int `json:"length"`
+}
+
+// A MarketCategory describes a category of products.
+// https://vk.com/dev/objects/market_item
+type MarketCategory struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Section struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"section"`
+}
+
+// A MarketAlbumObject contains information about collection of products.
+// https://vk.com/dev/objects/market_album
+type MarketAlbumObject struct {
+	ID      int          `json:"id"`
+	OwnerID int          `json:"owner_id"`
+	Title   string       `json:"title"`
+	Photo   *PhotoObject `json:"photo"`
+	// number of products
+	Count       int      `json:"count"`
+	UpdatedTime UnixTime `json:"updated_time"`
+	IsMain      BoolInt  `json:"is_main"`
+	IsHidden    BoolInt  `json:"is_hidden"`
+}
+
+// A MarketOrderObject contains information about order.
+// https://vk.com/dev/market.getOrderById
+type MarketOrderObject struct {
+	ID             int      `json:"id"`
+	GroupID        int      `json:"group_id"`
+	UserID         int      `json:"user_id"`
+	DisplayOrderID string   `json:"display_order_id"`
+	Date           UnixTime `json:"date"`
+	// 0 — new, 1 — approved, 2 — assembled, 3 — delivering,
+	// 4 — completed, 5 — cancelled, 6 — returned
+	Status          int         `json:"status"`
+	ItemsCount      int         `json:"items_count"`
+	TotalPrice      MarketPrice `json:"total_price"`
+	Comment         string      `json:"comment"`
+	MerchantComment string      `json:"merchant_comment"`
+	Address         string      `json:"address"`
+	TrackNumber     string      `json:"track_number"`
+	TrackLink       string      `json:"track_link"`
+	// weight in grams
+	Weight    int `json:"weight"`
+	Recipient struct {
+		Name        string `json:"name"`
+		Phone       string `json:"phone"`
+		DisplayText string `json:"display_text"`
+	} `json:"recipient"`
+	Delivery struct {
+		Address     string `json:"address"`
+		Type        string `json:"type"`
+		TrackNumber string `json:"track_number"`
+		TrackLink   string `json:"track_link"`
+	} `json:"delivery"`
+	PreviewOrderItems []MarketOrderItem `json:"preview_order_items"`
+}
+
+// A MarketOrderItem describes a product in the order.
+// https://vk.com/dev/market.getOrderItems
+type MarketOrderItem struct {
+	OwnerID  int              `json:"owner_id"`
+	ItemID   int              `json:"item_id"`
+	Price    MarketPrice      `json:"price"`
+	Quantity int              `json:"quantity"`
+	Item     MarketItemObject `json:"item"`
+	Title    string           `json:"title"`
+	Photo    *PhotoObject     `json:"photo"`
+	Variants []string         `json:"variants"`
+}
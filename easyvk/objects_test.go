@@ -0,0 +1,182 @@
+package easyvk
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+// unmodeledUserFields are sent by VK but not decoded into UserObject.
+var unmodeledUserFields = map[string]bool{
+	"track_code":           true,
+	"timezone":             true,
+	"online_mobile":        true,
+	"online_app":           true,
+	"can_be_invited_group": true,
+	"langs_full":           true,
+	"education_form_id":    true,
+	"education_status_id":  true,
+}
+
+// readUsers decodes the users.get response from testdata
+// and returns the raw response alongside.
+func readUsers(t *testing.T, name string) ([]UserObject, []interface{}) {
+	t.Helper()
+	data, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body struct {
+		Response []UserObject `json:"response"`
+	}
+	err = json.Unmarshal(data, &body)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	var raw struct {
+		Response []interface{} `json:"response"`
+	}
+	err = json.Unmarshal(data, &raw)
+	if err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+	return body.Response, raw.Response
+}
+
+// normalizeJSON turns bools into numbers as BoolInt encodes them.
+func normalizeJSON(v interface{}) interface{} {
+	if b, ok := v.(bool); ok {
+		if b {
+			return float64(1)
+		}
+		return float64(0)
+	}
+	return v
+}
+
+// diffJSON reports every value of want which is missing
+// in got or differs from it.
+func diffJSON(path string, want, got interface{}) []string {
+	switch want := want.(type) {
+	case map[string]interface{}:
+		got, ok := got.(map[string]interface{})
+		if !ok {
+			return []string{fmt.Sprintf("%s: got %v, want an object", path, got)}
+		}
+		keys := make([]string, 0, len(want))
+		for key := range want {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var diff []string
+		for _, key := range keys {
+			if unmodeledUserFields[key] {
+				continue
+			}
+			if _, ok := got[key]; !ok {
+				diff = append(diff, fmt.Sprintf("%s.%s: missing", path, key))
+				continue
+			}
+			diff = append(diff, diffJSON(path+"."+key, want[key], got[key])...)
+		}
+		return diff
+	case []interface{}:
+		got, ok := got.([]interface{})
+		if !ok || len(got) != len(want) {
+			return []string{fmt.Sprintf("%s: got %v, want %d items", path, got, len(want))}
+		}
+		var diff []string
+		for i := range want {
+			diff = append(diff, diffJSON(fmt.Sprintf("%s[%d]", path, i), want[i], got[i])...)
+		}
+		return diff
+	}
+	if normalizeJSON(want) != normalizeJSON(got) {
+		return []string{fmt.Sprintf("%s: got %v, want %v", path, got, want)}
+	}
+	return nil
+}
+
+// TestUserObjectRoundTrip checks that every field of the responses
+// is decoded: encoding the users back must give the same values.
+func TestUserObjectRoundTrip(t *testing.T) {
+	for _, name := range []string{"users_get_full.json", "users_get_list.json"} {
+		users, raw := readUsers(t, name)
+		data, err := json.Marshal(users)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		var encoded []interface{}
+		err = json.Unmarshal(data, &encoded)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		for _, d := range diffJSON(name, raw, encoded) {
+			t.Error(d)
+		}
+	}
+}
+
+func TestUserObjectFull(t *testing.T) {
+	users, _ := readUsers(t, "users_get_full.json")
+	if len(users) != 1 {
+		t.Fatalf("got %d users", len(users))
+	}
+	u := users[0]
+	if u.Sex != SexFemale || u.Relation != RelationSingle || u.Deactivated != NotDeactivated {
+		t.Errorf("got sex %v, relation %v, deactivated %q", u.Sex, u.Relation, u.Deactivated)
+	}
+	if !u.LastSeen.Time.Equal(time.Unix(1700000000, 0)) || u.LastSeen.Platform != PlatformAndroid {
+		t.Errorf("got last seen %+v", u.LastSeen)
+	}
+	if u.Exports != nil || u.RelationPartner != nil {
+		t.Error("missing sub-objects are filled")
+	}
+}
+
+func TestUserObjectList(t *testing.T) {
+	users, _ := readUsers(t, "users_get_list.json")
+
+	var open UserObject
+	open.ID, open.FirstName, open.LastName = 100002, "Ivan", "Primerov"
+	open.CanAccessClosed = true
+	open.Sex = SexMale
+	open.City.ID, open.City.Title = 1, "Moscow"
+	open.Country.ID, open.Country.Title = 1, "Russia"
+	open.Relation = RelationUnknown
+	open.Occupation = &UserOccupation{Type: "work", ID: 200002, Name: "Example Community"}
+	open.LastSeen.Platform = PlatformWeb
+	open.LastSeen.Time = UnixTime{time.Unix(1700000500, 0)}
+
+	var closed UserObject
+	closed.ID, closed.FirstName, closed.LastName = 100004, "Petr", "Obraztsov"
+	closed.IsClosed = true
+	closed.Sex = SexMale
+	closed.Relation = RelationMarried
+	closed.RelationPartner = &UserObject{ID: 100005, FirstName: "Olga", LastName: "Obraztsova"}
+	closed.LastSeen.Platform = PlatformIPhone
+	closed.LastSeen.Time = UnixTime{time.Unix(1700001000, 0)}
+
+	want := []UserObject{
+		open,
+		closed,
+		{ID: 100006, FirstName: "DELETED", Deactivated: Deleted},
+		{ID: 100007, FirstName: "Sergey", LastName: "Zablokirovanny", Deactivated: Banned},
+	}
+	if len(users) != len(want) {
+		t.Fatalf("got %d users", len(users))
+	}
+	for i := range want {
+		if !reflect.DeepEqual(users[i], want[i]) {
+			t.Errorf("user %d:\ngot  %+v\nwant %+v", i, users[i], want[i])
+		}
+	}
+	if users[1].Relation.String() != "married" {
+		t.Errorf("got relation %q", users[1].Relation.String())
+	}
+}
@@ -0,0 +1,133 @@
+package easyvk
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// ErrNoPhotoSize is returned when the photo has no suitable copy.
+var ErrNoPhotoSize = errors.New("photo has no suitable size")
+
+// A PhotoDownloader describes a way to download copies of photos.
+type PhotoDownloader struct {
+	// http.DefaultClient if nil.
+	Client *http.Client
+	// Number of parallel downloads in DownloadAll, 4 by default.
+	Workers int
+	// Select chooses the copy to download, Largest by default.
+	Select func(p *PhotoObject) (PhotoSize, bool)
+}
+
+// A PhotoDownload describes a result of DownloadAll.
+type PhotoDownload struct {
+	Photo PhotoObject
+	Size  PhotoSize
+	Path  string
+	Err   error
+}
+
+func (d *PhotoDownloader) client() *http.Client {
+	if d.Client != nil {
+		return d.Client
+	}
+	return http.DefaultClient
+}
+
+func (d *PhotoDownloader) choose(p *PhotoObject) (PhotoSize, error) {
+	choose := d.Select
+	if choose == nil {
+		choose = (*PhotoObject).Largest
+	}
+	size, ok := choose(p)
+	if !ok || size.Src == "" {
+		return PhotoSize{}, ErrNoPhotoSize
+	}
+	return size, nil
+}
+
+// Download writes the selected copy of the photo to w.
+func (d *PhotoDownloader) Download(p *PhotoObject, w io.Writer) (PhotoSize, error) {
+	size, err := d.choose(p)
+	if err != nil {
+		return PhotoSize{}, err
+	}
+	return size, d.fetch(size.Src, w)
+}
+
+// DownloadFile saves the selected copy of the photo to path.
+// The file appears only when it's completely downloaded.
+func (d *PhotoDownloader) DownloadFile(p *PhotoObject, path string) (PhotoSize, error) {
+	size, err := d.choose(p)
+	if err != nil {
+		return PhotoSize{}, err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return PhotoSize{}, err
+	}
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return PhotoSize{}, err
+	}
+	err = d.fetch(size.Src, f)
+	closeErr := f.Close()
+	if err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return PhotoSize{}, err
+	}
+	return size, os.Rename(tmp, path)
+}
+
+// DownloadAll saves photos in parallel to paths returned by path.
+// Results are in the same order as photos.
+func (d *PhotoDownloader) DownloadAll(photos []PhotoObject, path func(p *PhotoObject) string) []PhotoDownload {
+	workers := d.Workers
+	if workers <= 0 {
+		workers = 4
+	}
+
+	results := make([]PhotoDownload, len(photos))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range jobs {
+				r := &results[index]
+				r.Photo = photos[index]
+				r.Path = path(&r.Photo)
+				r.Size, r.Err = d.DownloadFile(&r.Photo, r.Path)
+			}
+		}()
+	}
+	for i := range photos {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+func (d *PhotoDownloader) fetch(url string, w io.Writer) error {
+	resp, err := d.client().Get(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("can't download %s: %s", url, resp.Status)
+	}
+	_, err = io.Copy(w, resp.Body)
+	return err
+}
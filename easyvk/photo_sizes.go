@@ -0,0 +1,205 @@
+package easyvk
+
+import "encoding/json"
+
+// PhotoSizeType is a type of the photo copy.
+// https://vk.com/dev/photo_sizes
+type PhotoSizeType string
+
+const (
+	// proportional copy with max width 75px
+	PhotoSizeS PhotoSizeType = "s"
+	// proportional copy with max width 130px
+	PhotoSizeM PhotoSizeType = "m"
+	// proportional copy with max width 604px
+	PhotoSizeX PhotoSizeType = "x"
+	// copies with max width 130px, 200px, 320px and 510px,
+	// cropped to 3:2 if the photo is wider
+	PhotoSizeO PhotoSizeType = "o"
+	PhotoSizeP PhotoSizeType = "p"
+	PhotoSizeQ PhotoSizeType = "q"
+	PhotoSizeR PhotoSizeType = "r"
+	// proportional copy with max width 807px
+	PhotoSizeY PhotoSizeType = "y"
+	// proportional copy with max size 1080x1024px
+	PhotoSizeZ PhotoSizeType = "z"
+	// proportional copy with max size 2560x2048px
+	PhotoSizeW PhotoSizeType = "w"
+)
+
+// photoSizeRank orders types from the smallest to the largest.
+var photoSizeRank = map[PhotoSizeType]int{
+	PhotoSizeS: 1,
+	PhotoSizeM: 2,
+	PhotoSizeO: 3,
+	PhotoSizeP: 4,
+	PhotoSizeQ: 5,
+	PhotoSizeR: 6,
+	PhotoSizeX: 7,
+	PhotoSizeY: 8,
+	PhotoSizeZ: 9,
+	PhotoSizeW: 10,
+}
+
+// photoSizeLimits are max width and height of the copy, 0 is no limit.
+var photoSizeLimits = map[PhotoSizeType][2]int{
+	PhotoSizeS: {75, 0},
+	PhotoSizeM: {130, 0},
+	PhotoSizeO: {130, 0},
+	PhotoSizeP: {200, 0},
+	PhotoSizeQ: {320, 0},
+	PhotoSizeR: {510, 0},
+	PhotoSizeX: {604, 0},
+	PhotoSizeY: {807, 0},
+	PhotoSizeZ: {1080, 1024},
+	PhotoSizeW: {2560, 2048},
+}
+
+// Cropped reports whether copies of the type may
+// be cropped to 3:2 and lose the aspect ratio.
+func (t PhotoSizeType) Cropped() bool {
+	switch t {
+	case PhotoSizeO, PhotoSizeP, PhotoSizeQ, PhotoSizeR:
+		return true
+	}
+	return false
+}
+
+// A PhotoSize describes a copy of the photo.
+// https://vk.com/dev/photo_sizes
+type PhotoSize struct {
+	Type PhotoSizeType `json:"type"`
+	// URL of the copy, newer API versions send it as url.
+	Src    string `json:"src"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// UnmarshalJSON decodes the size with the link in src or url.
+func (s *PhotoSize) UnmarshalJSON(data []byte) error {
+	type size PhotoSize
+	var v struct {
+		size
+		URL string `json:"url"`
+	}
+	err := json.Unmarshal(data, &v)
+	if err != nil {
+		return err
+	}
+	*s = PhotoSize(v.size)
+	if s.Src == "" {
+		s.Src = v.URL
+	}
+	return nil
+}
+
+// AllSizes returns copies of the photo.
+// If Sizes is empty they are made of Photo75…Photo2560 fields.
+func (p *PhotoObject) AllSizes() []PhotoSize {
+	if len(p.Sizes) > 0 {
+		return p.Sizes
+	}
+	legacy := []struct {
+		t   PhotoSizeType
+		src string
+	}{
+		{PhotoSizeS, p.Photo75},
+		{PhotoSizeM, p.Photo130},
+		{PhotoSizeX, p.Photo604},
+		{PhotoSizeY, p.Photo807},
+		{PhotoSizeZ, p.Photo1280},
+		{PhotoSizeW, p.Photo2560},
+	}
+	var sizes []PhotoSize
+	for _, l := range legacy {
+		if l.src != "" {
+			sizes = append(sizes, PhotoSize{Type: l.t, Src: l.src})
+		}
+	}
+	return sizes
+}
+
+// BySizeType returns the copy of the given type
+// and false if the photo has no such copy.
+func (p *PhotoObject) BySizeType(t PhotoSizeType) (PhotoSize, bool) {
+	for _, s := range p.AllSizes() {
+		if s.Type == t {
+			return s, true
+		}
+	}
+	return PhotoSize{}, false
+}
+
+// Largest returns the largest copy of the photo
+// and false if the photo has no copies.
+func (p *PhotoObject) Largest() (PhotoSize, bool) {
+	var best PhotoSize
+	bestArea := -1
+	for _, s := range p.AllSizes() {
+		w, h := p.dimensions(s)
+		area := w * h
+		if area > bestArea || area == bestArea && photoSizeRank[s.Type] > photoSizeRank[best.Type] {
+			best = s
+			bestArea = area
+		}
+	}
+	return best, bestArea >= 0
+}
+
+// ClosestTo returns the smallest copy which is at least width x height,
+// or the largest one if there is no such copy. Zero width or height
+// is not checked. Cropped copies are skipped if the photo is wider than 3:2.
+func (p *PhotoObject) ClosestTo(width, height int) (PhotoSize, bool) {
+	var best PhotoSize
+	bestArea := -1
+	for _, s := range p.AllSizes() {
+		if s.Type.Cropped() && p.widerThan3to2() {
+			continue
+		}
+		w, h := p.dimensions(s)
+		if w < width || h < height {
+			continue
+		}
+		area := w * h
+		if bestArea < 0 || area < bestArea {
+			best = s
+			bestArea = area
+		}
+	}
+	if bestArea < 0 {
+		return p.Largest()
+	}
+	return best, true
+}
+
+func (p *PhotoObject) widerThan3to2() bool {
+	return p.Width > 0 && p.Height > 0 && p.Width*2 > p.Height*3
+}
+
+// dimensions returns the size of the copy. If VK hasn't sent it,
+// the size is estimated by the limits of the type.
+func (p *PhotoObject) dimensions(s PhotoSize) (int, int) {
+	if s.Width > 0 && s.Height > 0 {
+		return s.Width, s.Height
+	}
+	limits, ok := photoSizeLimits[s.Type]
+	if !ok {
+		return 0, 0
+	}
+	w, h := p.Width, p.Height
+	if w <= 0 || h <= 0 {
+		// unknown aspect ratio, assume square
+		w, h = limits[0], limits[0]
+	}
+	if s.Type.Cropped() && w*2 > h*3 {
+		w = h * 3 / 2
+	}
+	scale := 1.0
+	if w > limits[0] {
+		scale = float64(limits[0]) / float64(w)
+	}
+	if limits[1] > 0 && float64(h)*scale > float64(limits[1]) {
+		scale = float64(limits[1]) / float64(h)
+	}
+	return int(float64(w) * scale), int(float64(h) * scale)
+}
@@ -0,0 +1,80 @@
+package easyvk
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPhotoSizes(t *testing.T) {
+	// sizes with dimensions sent by VK, photo is exactly 3:2
+	sized := &PhotoObject{Width: 1200, Height: 800, Sizes: []PhotoSize{
+		{Type: PhotoSizeS, Src: "s", Width: 75, Height: 50},
+		{Type: PhotoSizeM, Src: "m", Width: 130, Height: 87},
+		{Type: PhotoSizeO, Src: "o", Width: 130, Height: 87},
+		{Type: PhotoSizeQ, Src: "q", Width: 320, Height: 213},
+		{Type: PhotoSizeX, Src: "x", Width: 604, Height: 403},
+		{Type: PhotoSizeY, Src: "y", Width: 807, Height: 538},
+	}}
+	// panorama without dimensions of the copies
+	wide := &PhotoObject{Width: 3000, Height: 1000, Sizes: []PhotoSize{
+		{Type: PhotoSizeS, Src: "s"},
+		{Type: PhotoSizeM, Src: "m"},
+		{Type: PhotoSizeP, Src: "p"},
+		{Type: PhotoSizeQ, Src: "q"},
+		{Type: PhotoSizeX, Src: "x"},
+		{Type: PhotoSizeZ, Src: "z"},
+	}}
+	// old API versions send links in photo_* fields
+	legacy := &PhotoObject{Photo75: "s", Photo604: "x", Photo130: "m"}
+	empty := &PhotoObject{}
+
+	tests := []struct {
+		name          string
+		photo         *PhotoObject
+		width, height int
+		closest       string
+		largest       string
+	}{
+		{"sized", sized, 0, 0, "s", "y"},
+		{"sized 100", sized, 100, 0, "m", "y"},
+		{"sized 600x400", sized, 600, 400, "x", "y"},
+		{"sized 600x404", sized, 600, 404, "y", "y"},
+		{"sized too large", sized, 2000, 0, "y", "y"},
+		{"wide skips cropped", wide, 150, 0, "x", "z"},
+		{"wide by height", wide, 0, 300, "z", "z"},
+		{"legacy", legacy, 100, 0, "m", "x"},
+		{"legacy square", legacy, 604, 604, "x", "x"},
+		{"empty", empty, 100, 0, "", ""},
+	}
+	for _, tt := range tests {
+		closest, ok := tt.photo.ClosestTo(tt.width, tt.height)
+		if closest.Src != tt.closest || ok != (tt.closest != "") {
+			t.Errorf("%s: closest is %q, %v, want %q", tt.name, closest.Src, ok, tt.closest)
+		}
+		largest, ok := tt.photo.Largest()
+		if largest.Src != tt.largest || ok != (tt.largest != "") {
+			t.Errorf("%s: largest is %q, %v, want %q", tt.name, largest.Src, ok, tt.largest)
+		}
+	}
+
+	if s, ok := legacy.BySizeType(PhotoSizeM); !ok || s.Src != "m" {
+		t.Errorf("got %+v, %v", s, ok)
+	}
+	if _, ok := legacy.BySizeType(PhotoSizeW); ok {
+		t.Error("got missing size")
+	}
+}
+
+func TestPhotoSizeJSON(t *testing.T) {
+	var sizes []PhotoSize
+	err := json.Unmarshal([]byte(`[
+		{"type": "s", "src": "old", "width": 75, "height": 50},
+		{"type": "x", "url": "new", "width": 604, "height": 403}
+	]`), &sizes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sizes[0].Src != "old" || sizes[1].Src != "new" || sizes[1].Width != 604 {
+		t.Errorf("got %+v", sizes)
+	}
+}
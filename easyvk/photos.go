@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 // A Photos describes a set of methods
@@ -97,3 +98,867 @@ func (p *Photos) DeleteComment(ownerID, commentId int) (bool, error) {
 	}
 	return ok == 1, nil
 }
+
+// PhotosGetAlbumsParams provides fields for GetAlbums params.
+// https://vk.com/dev/photos.getAlbums
+type PhotosGetAlbumsParams struct {
+	OwnerID  int
+	AlbumIDs []int
+	Offset   int
+	Count    int
+	// Include service albums: wall, profile and saved photos.
+	NeedSystem bool
+	// Fill ThumbSrc of the albums.
+	NeedCovers bool
+	// Fill Sizes of the covers.
+	PhotoSizes bool
+}
+
+// PhotosGetAlbumsResponse describes a list of photo albums.
+// https://vk.com/dev/photos.getAlbums
+type PhotosGetAlbumsResponse struct {
+	Count int                `json:"count"`
+	Items []PhotoAlbumObject `json:"items"`
+}
+
+// GetAlbums returns a list of photo albums of a user or community.
+// https://vk.com/dev/photos.getAlbums
+func (p *Photos) GetAlbums(par PhotosGetAlbumsParams) (*PhotosGetAlbumsResponse, error) {
+	params := map[string]string{
+		"owner_id":    strconv.Itoa(par.OwnerID),
+		"offset":      strconv.Itoa(par.Offset),
+		"need_system": boolConverter(par.NeedSystem),
+		"need_covers": boolConverter(par.NeedCovers),
+		"photo_sizes": boolConverter(par.PhotoSizes),
+	}
+	if len(par.AlbumIDs) > 0 {
+		params["album_ids"] = strings.Join(intIdsToString(par.AlbumIDs), ",")
+	}
+	if par.Count != 0 {
+		params["count"] = strconv.Itoa(par.Count)
+	}
+	resp, err := p.vk.Request("photos.getAlbums", params)
+	if err != nil {
+		return nil, err
+	}
+	var albums PhotosGetAlbumsResponse
+	err = json.Unmarshal(resp, &albums)
+	if err != nil {
+		return nil, err
+	}
+	return &albums, nil
+}
+
+// PhotosCreateAlbumParams provides fields for CreateAlbum params.
+// https://vk.com/dev/photos.createAlbum
+type PhotosCreateAlbumParams struct {
+	Title string
+	// Set to create the album in the community.
+	GroupID     int
+	Description string
+	// Privacy settings like "all", "friends" or "nobody", user albums only.
+	PrivacyView    string
+	PrivacyComment string
+	// Only administrators can upload photos, community albums only.
+	UploadByAdminsOnly bool
+	CommentsDisabled   bool
+}
+
+// CreateAlbum creates an empty photo album.
+// https://vk.com/dev/photos.createAlbum
+func (p *Photos) CreateAlbum(par PhotosCreateAlbumParams) (*PhotoAlbumObject, error) {
+	params := map[string]string{
+		"title":                 par.Title,
+		"description":           par.Description,
+		"upload_by_admins_only": boolConverter(par.UploadByAdminsOnly),
+		"comments_disabled":     boolConverter(par.CommentsDisabled),
+	}
+	if par.GroupID != 0 {
+		params["group_id"] = strconv.Itoa(par.GroupID)
+	}
+	if par.PrivacyView != "" {
+		params["privacy_view"] = par.PrivacyView
+	}
+	if par.PrivacyComment != "" {
+		params["privacy_comment"] = par.PrivacyComment
+	}
+	resp, err := p.vk.Request("photos.createAlbum", params)
+	if err != nil {
+		return nil, err
+	}
+	var album PhotoAlbumObject
+	err = json.Unmarshal(resp, &album)
+	if err != nil {
+		return nil, err
+	}
+	return &album, nil
+}
+
+// PhotosEditAlbumParams provides fields for EditAlbum params.
+// Only non-nil fields are changed.
+// https://vk.com/dev/photos.editAlbum
+type PhotosEditAlbumParams struct {
+	AlbumID int
+	// Owner of the album, negative for communities.
+	OwnerID            int
+	Title              *string
+	Description        *string
+	PrivacyView        *string
+	PrivacyComment     *string
+	UploadByAdminsOnly *bool
+	CommentsDisabled   *bool
+}
+
+// EditAlbum edits information about a photo album.
+// https://vk.com/dev/photos.editAlbum
+func (p *Photos) EditAlbum(par PhotosEditAlbumParams) (bool, error) {
+	params := map[string]string{
+		"album_id": strconv.Itoa(par.AlbumID),
+		"owner_id": strconv.Itoa(par.OwnerID),
+	}
+	setString := func(name string, v *string) {
+		if v != nil {
+			params[name] = *v
+		}
+	}
+	setBool := func(name string, v *bool) {
+		if v != nil {
+			params[name] = boolConverter(*v)
+		}
+	}
+	setString("title", par.Title)
+	setString("description", par.Description)
+	setString("privacy_view", par.PrivacyView)
+	setString("privacy_comment", par.PrivacyComment)
+	setBool("upload_by_admins_only", par.UploadByAdminsOnly)
+	setBool("comments_disabled", par.CommentsDisabled)
+	return p.vk.requestBool("photos.editAlbum", params)
+}
+
+// DeleteAlbum deletes a photo album.
+// groupID is set if the album belongs to the community.
+// https://vk.com/dev/photos.deleteAlbum
+func (p *Photos) DeleteAlbum(albumID, groupID int) (bool, error) {
+	params := map[string]string{
+		"album_id": strconv.Itoa(albumID),
+	}
+	if groupID != 0 {
+		params["group_id"] = strconv.Itoa(groupID)
+	}
+	return p.vk.requestBool("photos.deleteAlbum", params)
+}
+
+// ReorderAlbums moves the album before or after another album,
+// zero before or after is not sent.
+// https://vk.com/dev/photos.reorderAlbums
+func (p *Photos) ReorderAlbums(ownerID, albumID, before, after int) (bool, error) {
+	params := map[string]string{
+		"owner_id": strconv.Itoa(ownerID),
+		"album_id": strconv.Itoa(albumID),
+	}
+	if before != 0 {
+		params["before"] = strconv.Itoa(before)
+	}
+	if after != 0 {
+		params["after"] = strconv.Itoa(after)
+	}
+	return p.vk.requestBool("photos.reorderAlbums", params)
+}
+
+// Service albums which can be used as PhotosGetParams.AlbumID.
+const (
+	WallAlbum    = "wall"
+	ProfileAlbum = "profile"
+	SavedAlbum   = "saved"
+)
+
+// PhotosGetParams provides fields for Get params.
+// https://vk.com/dev/photos.get
+type PhotosGetParams struct {
+	OwnerID int
+	// ID of the album or one of: wall, profile, saved.
+	AlbumID  string
+	PhotoIDs []int
+	// Reverse chronological order.
+	Rev bool
+	// Fill likes, comments and so on.
+	Extended   bool
+	PhotoSizes bool
+	Offset     int
+	Count      int
+}
+
+// PhotosGetResponse describes a list of photos.
+// https://vk.com/dev/photos.get
+type PhotosGetResponse struct {
+	Count int           `json:"count"`
+	Items []PhotoObject `json:"items"`
+}
+
+// Get returns a list of photos in an album.
+// https://vk.com/dev/photos.get
+func (p *Photos) Get(par PhotosGetParams) (*PhotosGetResponse, error) {
+	params := map[string]string{
+		"owner_id":    strconv.Itoa(par.OwnerID),
+		"album_id":    par.AlbumID,
+		"rev":         boolConverter(par.Rev),
+		"extended":    boolConverter(par.Extended),
+		"photo_sizes": boolConverter(par.PhotoSizes),
+		"offset":      strconv.Itoa(par.Offset),
+	}
+	if len(par.PhotoIDs) > 0 {
+		params["photo_ids"] = strings.Join(intIdsToString(par.PhotoIDs), ",")
+	}
+	if par.Count != 0 {
+		params["count"] = strconv.Itoa(par.Count)
+	}
+	return p.getList("photos.get", params)
+}
+
+// PhotosGetAllParams provides fields for GetAll params.
+// https://vk.com/dev/photos.getAll
+type PhotosGetAllParams struct {
+	OwnerID    int
+	Extended   bool
+	PhotoSizes bool
+	Offset     int
+	Count      int
+	// Skip photos from wall, profile and saved albums.
+	NoServiceAlbums bool
+	// Skip hidden photos.
+	SkipHidden bool
+}
+
+// GetAll returns photos of a user or community in anti-chronological order.
+// https://vk.com/dev/photos.getAll
+func (p *Photos) GetAll(par PhotosGetAllParams) (*PhotosGetResponse, error) {
+	params := map[string]string{
+		"owner_id":          strconv.Itoa(par.OwnerID),
+		"extended":          boolConverter(par.Extended),
+		"photo_sizes":       boolConverter(par.PhotoSizes),
+		"offset":            strconv.Itoa(par.Offset),
+		"no_service_albums": boolConverter(par.NoServiceAlbums),
+		"skip_hidden":       boolConverter(par.SkipHidden),
+	}
+	if par.Count != 0 {
+		params["count"] = strconv.Itoa(par.Count)
+	}
+	return p.getList("photos.getAll", params)
+}
+
+func (p *Photos) getList(method string, params map[string]string) (*PhotosGetResponse, error) {
+	resp, err := p.vk.Request(method, params)
+	if err != nil {
+		return nil, err
+	}
+	var photos PhotosGetResponse
+	err = json.Unmarshal(resp, &photos)
+	if err != nil {
+		return nil, err
+	}
+	return &photos, nil
+}
+
+// GetById returns photos by their IDs like "1_129207899"
+// or "1_129207899_access-key" for private photos.
+// https://vk.com/dev/photos.getById
+func (p *Photos) GetById(photos []string, extended, photoSizes bool) ([]PhotoObject, error) {
+	params := map[string]string{
+		"photos":      strings.Join(photos, ","),
+		"extended":    boolConverter(extended),
+		"photo_sizes": boolConverter(photoSizes),
+	}
+	resp, err := p.vk.Request("photos.getById", params)
+	if err != nil {
+		return nil, err
+	}
+	var items []PhotoObject
+	err = json.Unmarshal(resp, &items)
+	if err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// Move moves a photo to another album.
+// https://vk.com/dev/photos.move
+func (p *Photos) Move(ownerID, targetAlbumID, photoID int) (bool, error) {
+	params := map[string]string{
+		"owner_id":        strconv.Itoa(ownerID),
+		"target_album_id": strconv.Itoa(targetAlbumID),
+		"photo_id":        strconv.Itoa(photoID),
+	}
+	return p.vk.requestBool("photos.move", params)
+}
+
+// MakeCover makes a photo into an album cover.
+// https://vk.com/dev/photos.makeCover
+func (p *Photos) MakeCover(ownerID, photoID, albumID int) (bool, error) {
+	params := map[string]string{
+		"owner_id": strconv.Itoa(ownerID),
+		"photo_id": strconv.Itoa(photoID),
+		"album_id": strconv.Itoa(albumID),
+	}
+	return p.vk.requestBool("photos.makeCover", params)
+}
+
+// Edit changes the caption of a photo.
+// https://vk.com/dev/photos.edit
+func (p *Photos) Edit(ownerID, photoID int, caption string) (bool, error) {
+	params := map[string]string{
+		"owner_id": strconv.Itoa(ownerID),
+		"photo_id": strconv.Itoa(photoID),
+		"caption":  caption,
+	}
+	return p.vk.requestBool("photos.edit", params)
+}
+
+// Delete deletes a photo.
+// https://vk.com/dev/photos.delete
+func (p *Photos) Delete(ownerID, photoID int) (bool, error) {
+	params := map[string]string{
+		"owner_id": strconv.Itoa(ownerID),
+		"photo_id": strconv.Itoa(photoID),
+	}
+	return p.vk.requestBool("photos.delete", params)
+}
+
+// Restore restores a deleted photo.
+// https://vk.com/dev/photos.restore
+func (p *Photos) Restore(ownerID, photoID int) (bool, error) {
+	params := map[string]string{
+		"owner_id": strconv.Itoa(ownerID),
+		"photo_id": strconv.Itoa(photoID),
+	}
+	return p.vk.requestBool("photos.restore", params)
+}
+
+// Copy copies a photo to the "Saved photos" album
+// and returns ID of the new photo.
+// https://vk.com/dev/photos.copy
+func (p *Photos) Copy(ownerID, photoID int, accessKey string) (int, error) {
+	params := map[string]string{
+		"owner_id": strconv.Itoa(ownerID),
+		"photo_id": strconv.Itoa(photoID),
+	}
+	if accessKey != "" {
+		params["access_key"] = accessKey
+	}
+	resp, err := p.vk.Request("photos.copy", params)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(string(resp))
+}
+
+// PhotosGetUploadServerResponse describes the server address
+// for photo upload into an album.
+// https://vk.com/dev/photos.getUploadServer
+type PhotosGetUploadServerResponse struct {
+	UploadURL string `json:"upload_url"`
+	AlbumID   int    `json:"album_id"`
+	UserID    int    `json:"user_id"`
+}
+
+// GetUploadServer returns the server address for photo upload into the album.
+// groupID is set if the album belongs to the community.
+// https://vk.com/dev/photos.getUploadServer
+func (p *Photos) GetUploadServer(albumID, groupID int) (*PhotosGetUploadServerResponse, error) {
+	params := map[string]string{
+		"album_id": strconv.Itoa(albumID),
+	}
+	if groupID != 0 {
+		params["group_id"] = strconv.Itoa(groupID)
+	}
+	resp, err := p.vk.Request("photos.getUploadServer", params)
+	if err != nil {
+		return nil, err
+	}
+	var server PhotosGetUploadServerResponse
+	err = json.Unmarshal(resp, &server)
+	if err != nil {
+		return nil, err
+	}
+	return &server, nil
+}
+
+// PhotosSaveParams provides fields for Save params.
+// Server, PhotosList and Hash come from Upload.PhotoAlbum.
+// https://vk.com/dev/photos.save
+type PhotosSaveParams struct {
+	AlbumID    int
+	GroupID    int
+	Server     int
+	PhotosList string
+	Hash       string
+	Caption    string
+	Lat        float64
+	Long       float64
+}
+
+// Save saves photos after successful uploading into the album.
+// For upload look at file upload.go.
+// https://vk.com/dev/photos.save
+func (p *Photos) Save(par PhotosSaveParams) ([]PhotoObject, error) {
+	params := map[string]string{
+		"album_id":    strconv.Itoa(par.AlbumID),
+		"server":      strconv.Itoa(par.Server),
+		"photos_list": par.PhotosList,
+		"hash":        par.Hash,
+		"caption":     par.Caption,
+	}
+	if par.GroupID != 0 {
+		params["group_id"] = strconv.Itoa(par.GroupID)
+	}
+	if par.Lat != 0 || par.Long != 0 {
+		params["latitude"] = fmt.Sprint(par.Lat)
+		params["longitude"] = fmt.Sprint(par.Long)
+	}
+	resp, err := p.vk.Request("photos.save", params)
+	if err != nil {
+		return nil, err
+	}
+	var photos []PhotoObject
+	err = json.Unmarshal(resp, &photos)
+	if err != nil {
+		return nil, err
+	}
+	return photos, nil
+}
+
+// UploadToAlbum uploads files into the album and saves them.
+// VK accepts up to 5 files in one upload, more files
+// are sent in several uploads.
+func (p *Photos) UploadToAlbum(albumID, groupID int, caption string, filePaths []string) ([]PhotoObject, error) {
+	server, err := p.GetUploadServer(albumID, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	var photos []PhotoObject
+	for start := 0; start < len(filePaths); start += albumUploadFiles {
+		end := start + albumUploadFiles
+		if end > len(filePaths) {
+			end = len(filePaths)
+		}
+		uploaded, err := p.vk.Upload.PhotoAlbum(server.UploadURL, filePaths[start:end])
+		if err != nil {
+			return photos, err
+		}
+		saved, err := p.Save(PhotosSaveParams{
+			AlbumID:    albumID,
+			GroupID:    groupID,
+			Server:     uploaded.Server,
+			PhotosList: uploaded.PhotosList,
+			Hash:       uploaded.Hash,
+			Caption:    caption,
+		})
+		photos = append(photos, saved...)
+		if err != nil {
+			return photos, err
+		}
+	}
+	return photos, nil
+}
+
+// PhotosGetCommentsParams provides fields for GetComments params.
+// https://vk.com/dev/photos.getComments
+type PhotosGetCommentsParams struct {
+	OwnerID   int
+	PhotoID   int
+	NeedLikes bool
+	// Comment to start from, Offset is counted from it.
+	StartCommentID int
+	Offset         int
+	Count          int
+	// one of: asc (default), desc
+	Sort      string
+	AccessKey string
+	// Fill Profiles and Groups of comment authors.
+	Extended bool
+	Fields   UserFields
+}
+
+// PhotosGetCommentsResponse describes a list of comments on the photo.
+// https://vk.com/dev/photos.getComments
+type PhotosGetCommentsResponse struct {
+	Count    int             `json:"count"`
+	Items    []CommentObject `json:"items"`
+	Profiles []UserObject    `json:"profiles"`
+	Groups   []GroupObject   `json:"groups"`
+}
+
+// GetComments returns a list of comments on a photo.
+// https://vk.com/dev/photos.getComments
+func (p *Photos) GetComments(par PhotosGetCommentsParams) (*PhotosGetCommentsResponse, error) {
+	params := map[string]string{
+		"owner_id":   strconv.Itoa(par.OwnerID),
+		"photo_id":   strconv.Itoa(par.PhotoID),
+		"need_likes": boolConverter(par.NeedLikes),
+		"offset":     strconv.Itoa(par.Offset),
+		"sort":       par.Sort,
+		"access_key": par.AccessKey,
+		"extended":   boolConverter(par.Extended),
+		"fields":     par.Fields.String(),
+	}
+	if par.StartCommentID != 0 {
+		params["start_comment_id"] = strconv.Itoa(par.StartCommentID)
+	}
+	if par.Count != 0 {
+		params["count"] = strconv.Itoa(par.Count)
+	}
+	resp, err := p.vk.Request("photos.getComments", params)
+	if err != nil {
+		return nil, err
+	}
+	var comments PhotosGetCommentsResponse
+	err = json.Unmarshal(resp, &comments)
+	if err != nil {
+		return nil, err
+	}
+	return &comments, nil
+}
+
+// PhotosGetAllCommentsResponse describes a list of comments on photos.
+// https://vk.com/dev/photos.getAllComments
+type PhotosGetAllCommentsResponse struct {
+	Count int `json:"count"`
+	Items []struct {
+		CommentObject
+		// photo the comment belongs to
+		PID int `json:"pid"`
+	} `json:"items"`
+}
+
+// PhotosGetAllCommentsParams provides fields for GetAllComments params.
+// https://vk.com/dev/photos.getAllComments
+type PhotosGetAllCommentsParams struct {
+	OwnerID int
+	// Comments on all photos of the owner if zero.
+	AlbumID   int
+	NeedLikes bool
+	Offset    int
+	Count     int
+}
+
+// GetAllComments returns comments on photos of the album
+// or on all photos of the owner.
+// https://vk.com/dev/photos.getAllComments
+func (p *Photos) GetAllComments(par PhotosGetAllCommentsParams) (*PhotosGetAllCommentsResponse, error) {
+	params := map[string]string{
+		"owner_id":   strconv.Itoa(par.OwnerID),
+		"need_likes": boolConverter(par.NeedLikes),
+		"offset":     strconv.Itoa(par.Offset),
+	}
+	if par.AlbumID != 0 {
+		params["album_id"] = strconv.Itoa(par.AlbumID)
+	}
+	if par.Count != 0 {
+		params["count"] = strconv.Itoa(par.Count)
+	}
+	resp, err := p.vk.Request("photos.getAllComments", params)
+	if err != nil {
+		return nil, err
+	}
+	var comments PhotosGetAllCommentsResponse
+	err = json.Unmarshal(resp, &comments)
+	if err != nil {
+		return nil, err
+	}
+	return &comments, nil
+}
+
+// PhotosCreateCommentParams provides fields for CreateComment params.
+// https://vk.com/dev/photos.createComment
+type PhotosCreateCommentParams struct {
+	OwnerID int
+	PhotoID int
+	Message string
+	// comma separated list like photo100172_166443618
+	Attachments    string
+	FromGroup      bool
+	ReplyToComment int
+	StickerID      int
+	AccessKey      string
+	GUID           string
+}
+
+// CreateComment adds a new comment on the photo
+// and returns ID of the comment.
+// https://vk.com/dev/photos.createComment
+func (p *Photos) CreateComment(par PhotosCreateCommentParams) (int, error) {
+	params := map[string]string{
+		"owner_id":    strconv.Itoa(par.OwnerID),
+		"photo_id":    strconv.Itoa(par.PhotoID),
+		"message":     par.Message,
+		"attachments": par.Attachments,
+		"from_group":  boolConverter(par.FromGroup),
+		"access_key":  par.AccessKey,
+		"guid":        par.GUID,
+	}
+	if par.ReplyToComment != 0 {
+		params["reply_to_comment"] = strconv.Itoa(par.ReplyToComment)
+	}
+	if par.StickerID != 0 {
+		params["sticker_id"] = strconv.Itoa(par.StickerID)
+	}
+	resp, err := p.vk.Request("photos.createComment", params)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(string(resp))
+}
+
+// EditComment edits a comment on the photo.
+// https://vk.com/dev/photos.editComment
+func (p *Photos) EditComment(ownerID, commentID int, message, attachments string) (bool, error) {
+	params := map[string]string{
+		"owner_id":    strconv.Itoa(ownerID),
+		"comment_id":  strconv.Itoa(commentID),
+		"message":     message,
+		"attachments": attachments,
+	}
+	return p.vk.requestBool("photos.editComment", params)
+}
+
+// RestoreComment restores a deleted comment on the photo.
+// https://vk.com/dev/photos.restoreComment
+func (p *Photos) RestoreComment(ownerID, commentID int) (bool, error) {
+	params := map[string]string{
+		"owner_id":   strconv.Itoa(ownerID),
+		"comment_id": strconv.Itoa(commentID),
+	}
+	return p.vk.requestBool("photos.restoreComment", params)
+}
+
+// GetTags returns a list of tags on the photo.
+// https://vk.com/dev/photos.getTags
+func (p *Photos) GetTags(ownerID, photoID int, accessKey string) ([]PhotoTagObject, error) {
+	params := map[string]string{
+		"owner_id":   strconv.Itoa(ownerID),
+		"photo_id":   strconv.Itoa(photoID),
+		"access_key": accessKey,
+	}
+	resp, err := p.vk.Request("photos.getTags", params)
+	if err != nil {
+		return nil, err
+	}
+	var tags []PhotoTagObject
+	err = json.Unmarshal(resp, &tags)
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// PhotosPutTagParams provides fields for PutTag params.
+// Coordinates are percents of the photo size.
+// https://vk.com/dev/photos.putTag
+type PhotosPutTagParams struct {
+	OwnerID int
+	PhotoID int
+	UserID  int
+	X       float64
+	Y       float64
+	X2      float64
+	Y2      float64
+}
+
+// PutTag adds a tag on the photo and returns ID of the tag.
+// https://vk.com/dev/photos.putTag
+func (p *Photos) PutTag(par PhotosPutTagParams) (int, error) {
+	params := map[string]string{
+		"owner_id": strconv.Itoa(par.OwnerID),
+		"photo_id": strconv.Itoa(par.PhotoID),
+		"user_id":  strconv.Itoa(par.UserID),
+		"x":        fmt.Sprint(par.X),
+		"y":        fmt.Sprint(par.Y),
+		"x2":       fmt.Sprint(par.X2),
+		"y2":       fmt.Sprint(par.Y2),
+	}
+	resp, err := p.vk.Request("photos.putTag", params)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(string(resp))
+}
+
+// RemoveTag removes a tag from the photo.
+// https://vk.com/dev/photos.removeTag
+func (p *Photos) RemoveTag(ownerID, photoID, tagID int) (bool, error) {
+	params := map[string]string{
+		"owner_id": strconv.Itoa(ownerID),
+		"photo_id": strconv.Itoa(photoID),
+		"tag_id":   strconv.Itoa(tagID),
+	}
+	return p.vk.requestBool("photos.removeTag", params)
+}
+
+// ConfirmTag confirms a tag on the photo.
+// https://vk.com/dev/photos.confirmTag
+func (p *Photos) ConfirmTag(ownerID, photoID, tagID int) (bool, error) {
+	params := map[string]string{
+		"owner_id": strconv.Itoa(ownerID),
+		"photo_id": strconv.Itoa(photoID),
+		"tag_id":   strconv.Itoa(tagID),
+	}
+	return p.vk.requestBool("photos.confirmTag", params)
+}
+
+// PhotosGetNewTagsResponse describes a list of photos
+// with unconfirmed tags of the current user.
+// https://vk.com/dev/photos.getNewTags
+type PhotosGetNewTagsResponse struct {
+	Count int `json:"count"`
+	Items []struct {
+		PhotoObject
+		PlacerID   int      `json:"placer_id"`
+		TagCreated UnixTime `json:"tag_created"`
+		TagID      int      `json:"tag_id"`
+	} `json:"items"`
+}
+
+// GetNewTags returns photos with unconfirmed tags of the current user.
+// https://vk.com/dev/photos.getNewTags
+func (p *Photos) GetNewTags(offset, count int) (*PhotosGetNewTagsResponse, error) {
+	params := map[string]string{
+		"offset": strconv.Itoa(offset),
+	}
+	if count != 0 {
+		params["count"] = strconv.Itoa(count)
+	}
+	resp, err := p.vk.Request("photos.getNewTags", params)
+	if err != nil {
+		return nil, err
+	}
+	var tags PhotosGetNewTagsResponse
+	err = json.Unmarshal(resp, &tags)
+	if err != nil {
+		return nil, err
+	}
+	return &tags, nil
+}
+
+// PhotosGetMarketUploadServerResponse describes the server address
+// for photo upload of the product.
+// https://vk.com/dev/photos.getMarketUploadServer
+type PhotosGetMarketUploadServerResponse struct {
+	UploadURL string `json:"upload_url"`
+}
+
+// PhotosGetMarketUploadServerParams provides fields for GetMarketUploadServer params.
+// https://vk.com/dev/photos.getMarketUploadServer
+type PhotosGetMarketUploadServerParams struct {
+	GroupID   int
+	MainPhoto bool
+	// The main photo must be at least 400x400px and is cropped
+	// to a square at CropX, CropY with CropWidth side,
+	// the center square if they are zero.
+	CropX     int
+	CropY     int
+	CropWidth int
+}
+
+// GetMarketUploadServer returns the server address for photo upload of the product.
+// https://vk.com/dev/photos.getMarketUploadServer
+func (p *Photos) GetMarketUploadServer(par PhotosGetMarketUploadServerParams) (*PhotosGetMarketUploadServerResponse, error) {
+	params := map[string]string{
+		"group_id":   strconv.Itoa(par.GroupID),
+		"main_photo": boolConverter(par.MainPhoto),
+	}
+	if par.CropX != 0 {
+		params["crop_x"] = strconv.Itoa(par.CropX)
+	}
+	if par.CropY != 0 {
+		params["crop_y"] = strconv.Itoa(par.CropY)
+	}
+	if par.CropWidth != 0 {
+		params["crop_width"] = strconv.Itoa(par.CropWidth)
+	}
+	resp, err := p.vk.Request("photos.getMarketUploadServer", params)
+	if err != nil {
+		return nil, err
+	}
+	var server PhotosGetMarketUploadServerResponse
+	err = json.Unmarshal(resp, &server)
+	if err != nil {
+		return nil, err
+	}
+	return &server, nil
+}
+
+// PhotosSaveMarketPhotoParams provides fields for SaveMarketPhoto params.
+// All fields except GroupID come from Upload.PhotoMarket.
+// https://vk.com/dev/photos.saveMarketPhoto
+type PhotosSaveMarketPhotoParams struct {
+	GroupID  int
+	Photo    string
+	Server   int
+	Hash     string
+	CropData string
+	CropHash string
+}
+
+// SaveMarketPhoto saves the photo of the product after being uploaded.
+// For upload look at file upload.go.
+// https://vk.com/dev/photos.saveMarketPhoto
+func (p *Photos) SaveMarketPhoto(par PhotosSaveMarketPhotoParams) ([]PhotoObject, error) {
+	params := map[string]string{
+		"group_id": strconv.Itoa(par.GroupID),
+		"photo":    par.Photo,
+		"server":   strconv.Itoa(par.Server),
+		"hash":     par.Hash,
+	}
+	if par.CropData != "" {
+		params["crop_data"] = par.CropData
+		params["crop_hash"] = par.CropHash
+	}
+	resp, err := p.vk.Request("photos.saveMarketPhoto", params)
+	if err != nil {
+		return nil, err
+	}
+	var photos []PhotoObject
+	err = json.Unmarshal(resp, &photos)
+	if err != nil {
+		return nil, err
+	}
+	return photos, nil
+}
+
+// UploadMarketPhoto uploads the file as a photo of the product
+// to use in MarketAddParams. The main photo is cropped to the center square.
+func (p *Photos) UploadMarketPhoto(groupID int, mainPhoto bool, filePath string) (*PhotoObject, error) {
+	return p.uploadMarketPhoto(groupID, mainPhoto, filePath, func() {})
+}
+
+// uploadMarketPhoto calls wait before each API request.
+func (p *Photos) uploadMarketPhoto(groupID int, mainPhoto bool, filePath string, wait func()) (*PhotoObject, error) {
+	wait()
+	server, err := p.GetMarketUploadServer(PhotosGetMarketUploadServerParams{
+		GroupID:   groupID,
+		MainPhoto: mainPhoto,
+	})
+	if err != nil {
+		return nil, err
+	}
+	uploaded, err := p.vk.Upload.PhotoMarket(server.UploadURL, filePath)
+	if err != nil {
+		return nil, err
+	}
+	wait()
+	photos, err := p.SaveMarketPhoto(PhotosSaveMarketPhotoParams{
+		GroupID:  groupID,
+		Photo:    uploaded.Photo,
+		Server:   uploaded.Server,
+		Hash:     uploaded.Hash,
+		CropData: uploaded.CropData,
+		CropHash: uploaded.CropHash,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(photos) == 0 {
+		return nil, fmt.Errorf("photo %s hasn't been saved", filePath)
+	}
+	return &photos[0], nil
+}
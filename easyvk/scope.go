@@ -0,0 +1,171 @@
+package easyvk
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Scope is a bitmask of access permissions of the user token.
+// Convert it to the integer mask with uint(s) and back with Scope(mask).
+// https://vk.com/dev/permissions
+type Scope uint
+
+const (
+	ScopeNotify Scope = 1 << iota
+	ScopeFriends
+	ScopePhotos
+	ScopeAudio
+	ScopeVideo
+	_
+	ScopeStories
+	ScopePages
+	_
+	_
+	ScopeStatus
+	ScopeNotes
+	ScopeMessages
+	ScopeWall
+	_
+	ScopeAds
+	ScopeOffline
+	ScopeDocs
+	ScopeGroups
+	ScopeNotifications
+	ScopeStats
+	_
+	ScopeEmail
+	_
+	_
+	_
+	_
+	ScopeMarket
+)
+
+var scopeNames = []struct {
+	scope Scope
+	name  string
+}{
+	{ScopeNotify, "notify"},
+	{ScopeFriends, "friends"},
+	{ScopePhotos, "photos"},
+	{ScopeAudio, "audio"},
+	{ScopeVideo, "video"},
+	{ScopeStories, "stories"},
+	{ScopePages, "pages"},
+	{ScopeStatus, "status"},
+	{ScopeNotes, "notes"},
+	{ScopeMessages, "messages"},
+	{ScopeWall, "wall"},
+	{ScopeAds, "ads"},
+	{ScopeOffline, "offline"},
+	{ScopeDocs, "docs"},
+	{ScopeGroups, "groups"},
+	{ScopeNotifications, "notifications"},
+	{ScopeStats, "stats"},
+	{ScopeEmail, "email"},
+	{ScopeMarket, "market"},
+}
+
+// Has reports whether s contains all permissions of flags.
+func (s Scope) Has(flags Scope) bool {
+	return s&flags == flags
+}
+
+// String returns the scope as a list like "friends,wall".
+func (s Scope) String() string {
+	var names []string
+	for _, n := range scopeNames {
+		if s&n.scope != 0 {
+			names = append(names, n.name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// ParseScope parses a list like "friends,wall".
+func ParseScope(list string) (Scope, error) {
+	var s Scope
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		found := false
+		for _, n := range scopeNames {
+			if n.name == name {
+				s |= n.scope
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown scope %q", name)
+		}
+	}
+	return s, nil
+}
+
+// GroupScope is a bitmask of access permissions of the community token.
+// Convert it to the integer mask with uint(s) and back with GroupScope(mask).
+// https://vk.com/dev/permissions
+type GroupScope uint
+
+const (
+	GroupScopeStories   GroupScope = 1 << 0
+	GroupScopePhotos    GroupScope = 1 << 2
+	GroupScopeAppWidget GroupScope = 1 << 6
+	GroupScopeMessages  GroupScope = 1 << 12
+	GroupScopeDocs      GroupScope = 1 << 17
+	GroupScopeManage    GroupScope = 1 << 18
+)
+
+var groupScopeNames = []struct {
+	scope GroupScope
+	name  string
+}{
+	{GroupScopeStories, "stories"},
+	{GroupScopePhotos, "photos"},
+	{GroupScopeAppWidget, "app_widget"},
+	{GroupScopeMessages, "messages"},
+	{GroupScopeDocs, "docs"},
+	{GroupScopeManage, "manage"},
+}
+
+// Has reports whether s contains all permissions of flags.
+func (s GroupScope) Has(flags GroupScope) bool {
+	return s&flags == flags
+}
+
+// String returns the scope as a list like "manage,messages".
+func (s GroupScope) String() string {
+	var names []string
+	for _, n := range groupScopeNames {
+		if s&n.scope != 0 {
+			names = append(names, n.name)
+		}
+	}
+	return strings.Join(names, ",")
+}
+
+// ParseGroupScope parses a list like "manage,messages".
+func ParseGroupScope(list string) (GroupScope, error) {
+	var s GroupScope
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		found := false
+		for _, n := range groupScopeNames {
+			if n.name == name {
+				s |= n.scope
+				found = true
+				break
+			}
+		}
+		if !found {
+			return 0, fmt.Errorf("unknown community scope %q", name)
+		}
+	}
+	return s, nil
+}
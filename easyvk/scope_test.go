@@ -0,0 +1,70 @@
+package easyvk
+
+import "testing"
+
+func TestScope(t *testing.T) {
+	tests := []struct {
+		scope Scope
+		list  string
+		mask  uint
+	}{
+		{0, "", 0},
+		{ScopeFriends, "friends", 2},
+		{ScopeFriends | ScopePhotos | ScopeWall, "friends,photos,wall", 2 + 4 + 8192},
+		{ScopeOffline | ScopeMarket, "offline,market", 65536 + 134217728},
+	}
+	for _, tt := range tests {
+		if uint(tt.scope) != tt.mask {
+			t.Errorf("%s: got mask %d, want %d", tt.list, uint(tt.scope), tt.mask)
+		}
+		if got := tt.scope.String(); got != tt.list {
+			t.Errorf("%d: got %q, want %q", tt.mask, got, tt.list)
+		}
+		parsed, err := ParseScope(tt.list)
+		if err != nil || parsed != tt.scope {
+			t.Errorf("%q: got %d, %v, want %d", tt.list, parsed, err, tt.scope)
+		}
+	}
+
+	parsed, err := ParseScope(" wall , friends,")
+	if err != nil || parsed != ScopeWall|ScopeFriends {
+		t.Errorf("got %s, %v", parsed, err)
+	}
+	_, err = ParseScope("friends,manage")
+	if err == nil {
+		t.Error("unknown scope is parsed")
+	}
+	if !(ScopeWall | ScopeFriends).Has(ScopeWall) || ScopeWall.Has(ScopeWall|ScopeFriends) {
+		t.Error("wrong Has")
+	}
+}
+
+func TestGroupScope(t *testing.T) {
+	tests := []struct {
+		scope GroupScope
+		list  string
+		mask  uint
+	}{
+		{0, "", 0},
+		{GroupScopeManage, "manage", 262144},
+		{GroupScopeMessages | GroupScopeManage, "messages,manage", 4096 + 262144},
+		{GroupScopeStories | GroupScopePhotos | GroupScopeDocs, "stories,photos,docs", 1 + 4 + 131072},
+	}
+	for _, tt := range tests {
+		if uint(tt.scope) != tt.mask {
+			t.Errorf("%s: got mask %d, want %d", tt.list, uint(tt.scope), tt.mask)
+		}
+		if got := tt.scope.String(); got != tt.list {
+			t.Errorf("%d: got %q, want %q", tt.mask, got, tt.list)
+		}
+		parsed, err := ParseGroupScope(tt.list)
+		if err != nil || parsed != tt.scope {
+			t.Errorf("%q: got %d, %v, want %d", tt.list, parsed, err, tt.scope)
+		}
+	}
+
+	_, err := ParseGroupScope("manage,wall")
+	if err == nil {
+		t.Error("unknown scope is parsed")
+	}
+}
@@ -0,0 +1,177 @@
+package easyvk
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrServiceTokenRequired is returned by Secure methods
+// called with a user or community token.
+var ErrServiceTokenRequired = errors.New("method requires a service token")
+
+// A Secure describes a set of methods
+// for the server side of applications.
+// They require a service token, see WithClientCredentials.
+// https://vk.com/dev/secure
+type Secure struct {
+	vk *VK
+}
+
+// WithClientCredentials helps to initialize your VK object
+// with the service token of the application.
+// https://vk.com/dev/client_cred_flow
+func WithClientCredentials(clientID, clientSecret string) (*VK, error) {
+	query := url.Values{}
+	query.Set("client_id", clientID)
+	query.Set("client_secret", clientSecret)
+	query.Set("grant_type", "client_credentials")
+	query.Set("v", version)
+	token, err := requestOAuthToken(nil, query)
+	if err != nil {
+		return nil, err
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New("no access token in the response")
+	}
+
+	vk := WithToken(token.AccessToken)
+	vk.TokenKind = ServiceToken
+	vk.ClientSecret = clientSecret
+	return vk, nil
+}
+
+func (s *Secure) request(method string, params map[string]string) ([]byte, error) {
+	if s.vk.TokenKind != UnknownToken && s.vk.TokenKind != ServiceToken {
+		return nil, ErrServiceTokenRequired
+	}
+	if s.vk.ClientSecret != "" {
+		params["client_secret"] = s.vk.ClientSecret
+	}
+	return s.vk.Request(method, params)
+}
+
+// A SecureCheckTokenResponse describes the checked user token.
+// https://vk.com/dev/secure.checkToken
+type SecureCheckTokenResponse struct {
+	Success BoolInt  `json:"success"`
+	UserID  int      `json:"user_id"`
+	Date    UnixTime `json:"date"`
+	Expire  UnixTime `json:"expire"`
+}
+
+// CheckToken checks the user authentication in IFrame and Flash apps
+// using the access_token parameter. ip is optional.
+// https://vk.com/dev/secure.checkToken
+func (s *Secure) CheckToken(token, ip string) (SecureCheckTokenResponse, error) {
+	params := map[string]string{
+		"token": token,
+	}
+	if ip != "" {
+		params["ip"] = ip
+	}
+	resp, err := s.request("secure.checkToken", params)
+	if err != nil {
+		return SecureCheckTokenResponse{}, err
+	}
+	var info SecureCheckTokenResponse
+	err = json.Unmarshal(resp, &info)
+	if err != nil {
+		return SecureCheckTokenResponse{}, err
+	}
+	return info, nil
+}
+
+// SendNotification sends notification to the users who have installed the app.
+// Returns ids of the users who got the notification.
+// https://vk.com/dev/secure.sendNotification
+func (s *Secure) SendNotification(userIds []int, message string) ([]int, error) {
+	params := map[string]string{
+		"user_ids": strings.Join(intIdsToString(userIds), ","),
+		"message":  message,
+	}
+	resp, err := s.request("secure.sendNotification", params)
+	if err != nil {
+		return nil, err
+	}
+	var list string
+	err = json.Unmarshal(resp, &list)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	for _, id := range strings.Split(list, ",") {
+		if id == "" {
+			continue
+		}
+		n, err := strconv.Atoi(id)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, n)
+	}
+	return ids, nil
+}
+
+// AddAppEvent adds user activity information to an application.
+// https://vk.com/dev/secure.addAppEvent
+func (s *Secure) AddAppEvent(userID, activityID, value int) (bool, error) {
+	params := map[string]string{
+		"user_id":     fmt.Sprint(userID),
+		"activity_id": fmt.Sprint(activityID),
+		"value":       fmt.Sprint(value),
+	}
+	resp, err := s.request("secure.addAppEvent", params)
+	if err != nil {
+		return false, err
+	}
+	ok, err := strconv.ParseUint(string(resp), 10, 8)
+	if err != nil {
+		return false, err
+	}
+	return ok == 1, nil
+}
+
+// A SecureSMS describes SMS notification sent by the application.
+// https://vk.com/dev/secure.getSMSHistory
+type SecureSMS struct {
+	ID      int      `json:"id"`
+	AppID   int      `json:"app_id"`
+	UserID  int      `json:"user_id"`
+	Date    UnixTime `json:"date"`
+	Message string   `json:"message"`
+}
+
+// GetSMSHistory shows a list of SMS notifications sent by the application.
+// Zero userID, dates and limit are not sent.
+// https://vk.com/dev/secure.getSMSHistory
+func (s *Secure) GetSMSHistory(userID int, dateFrom, dateTo time.Time, limit int) ([]SecureSMS, error) {
+	params := map[string]string{}
+	if limit != 0 {
+		params["limit"] = fmt.Sprint(limit)
+	}
+	if userID != 0 {
+		params["user_id"] = fmt.Sprint(userID)
+	}
+	if !dateFrom.IsZero() {
+		params["date_from"] = fmt.Sprint(dateFrom.Unix())
+	}
+	if !dateTo.IsZero() {
+		params["date_to"] = fmt.Sprint(dateTo.Unix())
+	}
+	resp, err := s.request("secure.getSMSHistory", params)
+	if err != nil {
+		return nil, err
+	}
+	var list []SecureSMS
+	err = json.Unmarshal(resp, &list)
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
@@ -0,0 +1,28 @@
+package easyvk
+
+// TokenKind is a kind of the access token.
+// https://vk.com/dev/access_token
+type TokenKind int
+
+const (
+	// UnknownToken - the kind has not been set or detected
+	UnknownToken TokenKind = iota
+	// UserToken - token of the user
+	UserToken
+	// GroupToken - token of the community
+	GroupToken
+	// ServiceToken - service token of the application
+	ServiceToken
+)
+
+func (k TokenKind) String() string {
+	switch k {
+	case UserToken:
+		return "user"
+	case GroupToken:
+		return "community"
+	case ServiceToken:
+		return "service"
+	}
+	return "unknown"
+}
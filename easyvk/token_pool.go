@@ -0,0 +1,228 @@
+package easyvk
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	// tooManyRequestsCode is returned when more than
+	// the allowed number of requests per second have been sent.
+	tooManyRequestsCode = 6
+	// rateLimitCode is returned when the daily
+	// limit of the method has been reached.
+	rateLimitCode = 29
+)
+
+// ErrNoTokens is returned by requests through the pool
+// when every token is cooling down.
+var ErrNoTokens = errors.New("no tokens available in the pool")
+
+// A TokenPool describes a set of tokens requests are spread across.
+// Every token has its own budget of requests per second,
+// tokens are taken out of rotation for a while after
+// errors 5 (auth failed) and 29 (rate limit reached).
+// Requests failed with these errors or with error 6
+// are retried with another token of the pool.
+// It is safe for concurrent use.
+type TokenPool struct {
+	// How long a token rests after error 5, 1 hour by default.
+	AuthFailedCooldown time.Duration
+	// How long a token rests after error 29, 1 hour by default.
+	RateLimitCooldown time.Duration
+
+	mu     sync.Mutex
+	tokens []*poolToken
+	next   int
+}
+
+type poolToken struct {
+	token    string
+	interval time.Duration
+	// the time the next request with the token may start
+	nextAt        time.Time
+	disabledUntil time.Time
+	requests      int
+	lastErr       error
+}
+
+// A TokenStats describes the state of the token in the pool.
+type TokenStats struct {
+	Token         string
+	Requests      int
+	DisabledUntil time.Time
+	LastError     error
+}
+
+// defaultCooldown is how long a token rests
+// if the pool doesn't set the time.
+const defaultCooldown = time.Hour
+
+// NewTokenPool returns an empty pool.
+func NewTokenPool() *TokenPool {
+	return &TokenPool{
+		AuthFailedCooldown: defaultCooldown,
+		RateLimitCooldown:  defaultCooldown,
+	}
+}
+
+func cooldown(d time.Duration) time.Duration {
+	if d == 0 {
+		return defaultCooldown
+	}
+	return d
+}
+
+// WithTokenPool helps to initialize your VK object
+// which sends every request with a token from the pool.
+func WithTokenPool(pool *TokenPool) *VK {
+	vk := WithToken("")
+	vk.Pool = pool
+	return vk
+}
+
+// Add adds the token to the pool. requestsPerSecond is the budget
+// of the token, e.g. 3 for user tokens and 20 for community tokens.
+func (p *TokenPool) Add(token string, requestsPerSecond int) {
+	if requestsPerSecond <= 0 {
+		requestsPerSecond = 1
+	}
+	p.mu.Lock()
+	p.tokens = append(p.tokens, &poolToken{
+		token:    token,
+		interval: time.Second / time.Duration(requestsPerSecond),
+	})
+	p.mu.Unlock()
+}
+
+// Stats returns the state of every token in the pool.
+func (p *TokenPool) Stats() []TokenStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := make([]TokenStats, len(p.tokens))
+	for i, t := range p.tokens {
+		stats[i] = TokenStats{
+			Token:         t.token,
+			Requests:      t.requests,
+			DisabledUntil: t.disabledUntil,
+			LastError:     t.lastErr,
+		}
+	}
+	return stats
+}
+
+// pick returns the available token which
+// can be used the soonest, nil if there is no one.
+func (p *TokenPool) pick(now time.Time) *poolToken {
+	var best *poolToken
+	bestIndex := 0
+	for i := 0; i < len(p.tokens); i++ {
+		// start from the next token to spread requests evenly
+		index := (p.next + i) % len(p.tokens)
+		t := p.tokens[index]
+		if now.Before(t.disabledUntil) {
+			continue
+		}
+		if best == nil || t.nextAt.Before(best.nextAt) {
+			best = t
+			bestIndex = index
+		}
+	}
+	if best != nil {
+		p.next = bestIndex + 1
+	}
+	return best
+}
+
+// reserve books the next slot of the token and
+// returns how long to wait until it comes.
+func (t *poolToken) reserve(now time.Time) time.Duration {
+	if t.nextAt.Before(now) {
+		t.nextAt = now
+	}
+	wait := t.nextAt.Sub(now)
+	t.nextAt = t.nextAt.Add(t.interval)
+	t.requests++
+	return wait
+}
+
+// acquire returns a token for the request, waiting for
+// its budget. If pinned is set, only that token is used.
+func (p *TokenPool) acquire(pinned string) (string, error) {
+	p.mu.Lock()
+	now := time.Now()
+	var t *poolToken
+	if pinned != "" {
+		for _, pt := range p.tokens {
+			if pt.token == pinned && !now.Before(pt.disabledUntil) {
+				t = pt
+				break
+			}
+		}
+	} else {
+		t = p.pick(now)
+	}
+	if t == nil {
+		p.mu.Unlock()
+		return "", ErrNoTokens
+	}
+	wait := t.reserve(now)
+	p.mu.Unlock()
+
+	if wait > 0 {
+		time.Sleep(wait)
+	}
+	return t.token, nil
+}
+
+// report takes the token out of rotation if err says so.
+func (p *TokenPool) report(token string, err error) {
+	vkErr, ok := err.(*Error)
+	if !ok {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, t := range p.tokens {
+		if t.token != token {
+			continue
+		}
+		t.lastErr = err
+		now := time.Now()
+		switch vkErr.Code {
+		case authFailedCode:
+			t.disabledUntil = now.Add(cooldown(p.AuthFailedCooldown))
+		case rateLimitCode:
+			t.disabledUntil = now.Add(cooldown(p.RateLimitCooldown))
+		case tooManyRequestsCode:
+			// VK counts differently, give the token a second to rest
+			if t.nextAt.Before(now.Add(time.Second)) {
+				t.nextAt = now.Add(time.Second)
+			}
+		}
+		return
+	}
+}
+
+// Pin returns a VK object which sends all requests with one
+// token of the pool, e.g. for a sequence of dependent calls.
+// Without a pool it returns vk itself.
+func (vk *VK) Pin() (*VK, error) {
+	if vk.Pool == nil || vk.pinned != "" {
+		return vk, nil
+	}
+
+	vk.Pool.mu.Lock()
+	t := vk.Pool.pick(time.Now())
+	vk.Pool.mu.Unlock()
+	if t == nil {
+		return nil, ErrNoTokens
+	}
+
+	pinned := *vk
+	pinned.pinned = t.token
+	pinned.initSections()
+	return &pinned, nil
+}
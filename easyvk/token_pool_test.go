@@ -0,0 +1,166 @@
+package easyvk
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTokenPoolPick(t *testing.T) {
+	pool := NewTokenPool()
+	pool.Add("a", 1)
+	pool.Add("b", 1)
+	pool.Add("c", 1)
+	now := time.Now()
+
+	// tokens are used in turn while all of them are free
+	var got []string
+	for i := 0; i < 3; i++ {
+		token := pool.pick(now)
+		token.reserve(now)
+		got = append(got, token.token)
+	}
+	if fmt.Sprint(got) != "[a b c]" {
+		t.Errorf("got %v, want [a b c]", got)
+	}
+
+	// then the one which is free the soonest
+	pool.tokens[1].nextAt = now.Add(-time.Second)
+	if token := pool.pick(now); token.token != "b" {
+		t.Errorf("got %s, want b", token.token)
+	}
+
+	pool.tokens[1].disabledUntil = now.Add(time.Minute)
+	for i := 0; i < 3; i++ {
+		if token := pool.pick(now); token.token == "b" {
+			t.Error("disabled token is picked")
+		}
+	}
+	if token := pool.pick(now.Add(2 * time.Minute)); token == nil {
+		t.Error("no token after cool-down")
+	}
+
+	for _, token := range pool.tokens {
+		token.disabledUntil = now.Add(time.Minute)
+	}
+	if token := pool.pick(now); token != nil {
+		t.Errorf("got %s, want no token", token.token)
+	}
+}
+
+func TestTokenPoolReport(t *testing.T) {
+	tests := []struct {
+		code     int
+		disabled bool
+		delayed  bool
+	}{
+		{authFailedCode, true, false},
+		{rateLimitCode, true, false},
+		{tooManyRequestsCode, false, true},
+		{100, false, false},
+	}
+	for _, tt := range tests {
+		pool := NewTokenPool()
+		pool.Add("a", 3)
+		before := time.Now()
+		pool.report("a", &Error{Code: tt.code})
+
+		stats := pool.Stats()[0]
+		if disabled := stats.DisabledUntil.After(before); disabled != tt.disabled {
+			t.Errorf("code %d: got disabled %v, want %v", tt.code, disabled, tt.disabled)
+		}
+		if delayed := pool.tokens[0].nextAt.After(before); delayed != tt.delayed {
+			t.Errorf("code %d: got delayed %v, want %v", tt.code, delayed, tt.delayed)
+		}
+		if stats.LastError == nil {
+			t.Errorf("code %d: last error is not kept", tt.code)
+		}
+	}
+
+	// zero pool rests tokens for the default time too
+	pool := &TokenPool{}
+	pool.Add("a", 3)
+	pool.report("a", &Error{Code: rateLimitCode})
+	if rest := time.Until(pool.Stats()[0].DisabledUntil); rest < defaultCooldown-time.Minute {
+		t.Errorf("got cool-down %s in zero pool", rest)
+	}
+
+	_, err := NewTokenPool().acquire("")
+	if err != ErrNoTokens {
+		t.Errorf("got %v from empty pool, want ErrNoTokens", err)
+	}
+}
+
+// poolServer answers with error code of the token
+// or with 1 if there is no code for it.
+func poolServer(codes map[string]int) (*httptest.Server, map[string]int) {
+	var mu sync.Mutex
+	calls := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := r.FormValue("access_token")
+		mu.Lock()
+		calls[token]++
+		mu.Unlock()
+		if code, ok := codes[token]; ok {
+			fmt.Fprintf(w, `{"error": {"error_code": %d, "error_msg": "error"}}`, code)
+			return
+		}
+		fmt.Fprint(w, `{"response": 1}`)
+	}))
+	return srv, calls
+}
+
+func TestTokenPoolRetry(t *testing.T) {
+	tests := []struct {
+		name    string
+		codes   map[string]int
+		pinned  bool
+		ok      bool
+		attempt int
+	}{
+		{"limited token", map[string]int{"a": rateLimitCode}, false, true, 2},
+		{"too many requests", map[string]int{"a": tooManyRequestsCode}, false, true, 2},
+		{"request error", map[string]int{"a": 100}, false, false, 1},
+		{"pinned", map[string]int{"a": rateLimitCode}, true, false, 1},
+		{"all limited", map[string]int{"a": rateLimitCode, "b": rateLimitCode, "c": rateLimitCode, "d": rateLimitCode}, false, false, poolAttempts},
+	}
+	for _, tt := range tests {
+		srv, calls := poolServer(tt.codes)
+		pool := NewTokenPool()
+		for _, token := range []string{"a", "b", "c", "d"} {
+			pool.Add(token, 100)
+		}
+		vk := WithTokenPool(pool)
+		vk.ApiUrl = srv.URL + "/"
+		if tt.pinned {
+			pool.next = 0
+			var err error
+			vk, err = vk.Pin()
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		// the first request goes with token a
+		pool.next = 0
+		ok, err := vk.requestBool("status.get", nil)
+		srv.Close()
+
+		if ok != tt.ok {
+			t.Errorf("%s: got %v, %v, want %v", tt.name, ok, err, tt.ok)
+		}
+		if _, isVK := err.(*Error); err != nil && !isVK {
+			t.Errorf("%s: got %v, want VK error", tt.name, err)
+		}
+		total := 0
+		for _, n := range calls {
+			total += n
+		}
+		if total != tt.attempt {
+			t.Errorf("%s: got %d attempts, want %d", tt.name, total, tt.attempt)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package easyvk
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// authFailedCode is the code of VK error
+// returned when the token is invalid or expired.
+const authFailedCode = 5
+
+// A StoredToken describes a token kept in the TokenStore.
+type StoredToken struct {
+	AccessToken string `json:"access_token"`
+	UserID      int    `json:"user_id"`
+	// Zero if the token never expires.
+	ExpiresAt  time.Time  `json:"expires_at"`
+	Kind       TokenKind  `json:"kind"`
+	Scope      Scope      `json:"scope"`
+	GroupScope GroupScope `json:"group_scope"`
+}
+
+// VK returns VK object with the token.
+func (t *StoredToken) VK() *VK {
+	vk := WithToken(t.AccessToken)
+	vk.TokenKind = t.Kind
+	vk.Scope = t.Scope
+	vk.GroupScope = t.GroupScope
+	return vk
+}
+
+// Expired reports whether the token has expired.
+func (t *StoredToken) Expired() bool {
+	return !t.ExpiresAt.IsZero() && time.Now().After(t.ExpiresAt)
+}
+
+// A TokenStore describes a storage of tokens
+// to reuse them instead of signing in again.
+type TokenStore interface {
+	// Load returns nil if there is no token for the key.
+	Load(key string) (*StoredToken, error)
+	Save(key string, t *StoredToken) error
+	Delete(key string) error
+}
+
+// LoadToken returns VK object with the stored token
+// or nil if there is no token or it is not valid anymore.
+// The token is checked by a users.get request,
+// invalid tokens are deleted from the store.
+func LoadToken(store TokenStore, key string) (*VK, error) {
+	t, err := store.Load(key)
+	if err != nil || t == nil {
+		return nil, err
+	}
+	if t.Expired() {
+		return nil, store.Delete(key)
+	}
+
+	vk := t.VK()
+	_, err = vk.Users.Get(nil, nil, "")
+	if vkErr, ok := err.(*Error); ok && vkErr.Code == authFailedCode {
+		return nil, store.Delete(key)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return vk, nil
+}
+
+// A MemoryTokenStore keeps tokens in memory.
+type MemoryTokenStore struct {
+	mu     sync.Mutex
+	tokens map[string]StoredToken
+}
+
+// NewMemoryTokenStore returns an empty MemoryTokenStore.
+func NewMemoryTokenStore() *MemoryTokenStore {
+	return &MemoryTokenStore{tokens: map[string]StoredToken{}}
+}
+
+// Load returns the token by key.
+func (s *MemoryTokenStore) Load(key string) (*StoredToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tokens[key]
+	if !ok {
+		return nil, nil
+	}
+	return &t, nil
+}
+
+// Save keeps the token by key.
+func (s *MemoryTokenStore) Save(key string, t *StoredToken) error {
+	s.mu.Lock()
+	s.tokens[key] = *t
+	s.mu.Unlock()
+	return nil
+}
+
+// Delete removes the token by key.
+func (s *MemoryTokenStore) Delete(key string) error {
+	s.mu.Lock()
+	delete(s.tokens, key)
+	s.mu.Unlock()
+	return nil
+}
+
+// A FileTokenStore keeps tokens in a file
+// encrypted with AES-GCM.
+type FileTokenStore struct {
+	path string
+	aead cipher.AEAD
+	mu   sync.Mutex
+}
+
+// NewFileTokenStore returns a store which keeps tokens in the file at path.
+// Tokens are encrypted with a key derived from the given key,
+// the same key must be used to read them later.
+func NewFileTokenStore(path string, key []byte) (*FileTokenStore, error) {
+	if len(key) == 0 {
+		return nil, errors.New("empty encryption key")
+	}
+	sum := sha256.Sum256(key)
+	block, err := aes.NewCipher(sum[:])
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &FileTokenStore{path: path, aead: aead}, nil
+}
+
+func (s *FileTokenStore) read() (map[string]StoredToken, error) {
+	tokens := map[string]StoredToken{}
+	data, err := ioutil.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return tokens, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	size := s.aead.NonceSize()
+	if len(data) < size {
+		return nil, errors.New("token store file is corrupted")
+	}
+	plain, err := s.aead.Open(nil, data[:size], data[size:], nil)
+	if err != nil {
+		return nil, errors.New("can't decrypt token store, wrong key?")
+	}
+	err = json.Unmarshal(plain, &tokens)
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+func (s *FileTokenStore) write(tokens map[string]StoredToken) error {
+	plain, err := json.Marshal(tokens)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	_, err = io.ReadFull(rand.Reader, nonce)
+	if err != nil {
+		return err
+	}
+	data := s.aead.Seal(nonce, nonce, plain, nil)
+
+	err = os.MkdirAll(filepath.Dir(s.path), 0700)
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	err = ioutil.WriteFile(tmp, data, 0600)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// Load returns the token by key.
+func (s *FileTokenStore) Load(key string) (*StoredToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+	t, ok := tokens[key]
+	if !ok {
+		return nil, nil
+	}
+	return &t, nil
+}
+
+// Save keeps the token by key.
+func (s *FileTokenStore) Save(key string, t *StoredToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+	tokens[key] = *t
+	return s.write(tokens)
+}
+
+// Delete removes the token by key.
+func (s *FileTokenStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	tokens, err := s.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := tokens[key]; !ok {
+		return nil
+	}
+	delete(tokens, key)
+	return s.write(tokens)
+}
@@ -0,0 +1,94 @@
+package easyvk
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFileTokenStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens", "store")
+	store, err := NewFileTokenStore(path, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token := &StoredToken{
+		AccessToken: "abcdef0123456789",
+		UserID:      1,
+		ExpiresAt:   time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		Kind:        UserToken,
+		Scope:       ScopeWall | ScopeOffline,
+	}
+	if err := store.Save("user", token); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Save("group", &StoredToken{AccessToken: "group", Kind: GroupToken}); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte(token.AccessToken)) {
+		t.Error("token is stored in plain text")
+	}
+
+	// a new store reads tokens saved by another one
+	other, err := NewFileTokenStore(path, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := other.Load("user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, token) {
+		t.Errorf("got %+v, want %+v", got, token)
+	}
+
+	if err := other.Delete("user"); err != nil {
+		t.Fatal(err)
+	}
+	got, err = store.Load("user")
+	if err != nil || got != nil {
+		t.Errorf("got %+v, %v for deleted token", got, err)
+	}
+	got, err = store.Load("group")
+	if err != nil || got == nil || got.AccessToken != "group" {
+		t.Errorf("got %+v, %v for group token", got, err)
+	}
+
+	wrong, err := NewFileTokenStore(path, []byte("wrong"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = wrong.Load("group")
+	if err == nil || !strings.Contains(err.Error(), "wrong key") {
+		t.Errorf("got %v with wrong key", err)
+	}
+
+	_, err = NewFileTokenStore(path, nil)
+	if err == nil {
+		t.Error("store with empty key is created")
+	}
+}
+
+func TestFileTokenStoreMissingFile(t *testing.T) {
+	store, err := NewFileTokenStore(filepath.Join(t.TempDir(), "none"), []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := store.Load("user")
+	if err != nil || got != nil {
+		t.Errorf("got %+v, %v", got, err)
+	}
+	if err := store.Delete("user"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,203 @@
+package easyvk
+
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// BoolInt is a flag which VK sends as 0 or 1.
+type BoolInt bool
+
+// MarshalJSON encodes the flag as 0 or 1.
+func (b BoolInt) MarshalJSON() ([]byte, error) {
+	if b {
+		return []byte("1"), nil
+	}
+	return []byte("0"), nil
+}
+
+// UnmarshalJSON decodes the flag from a number or a bool.
+func (b *BoolInt) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case "null":
+		return nil
+	case "true":
+		*b = true
+		return nil
+	case "false":
+		*b = false
+		return nil
+	}
+	n, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return err
+	}
+	*b = n != 0
+	return nil
+}
+
+// UnixTime is a time which VK sends as unix timestamp.
+// Zero timestamp is decoded to the zero time.
+type UnixTime struct {
+	time.Time
+}
+
+// MarshalJSON encodes the time as unix timestamp.
+func (t UnixTime) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("0"), nil
+	}
+	return []byte(strconv.FormatInt(t.Unix(), 10)), nil
+}
+
+// UnmarshalJSON decodes the time from unix timestamp.
+func (t *UnixTime) UnmarshalJSON(data []byte) error {
+	data = bytes.Trim(data, `"`)
+	if string(data) == "null" {
+		return nil
+	}
+	var sec int64
+	err := json.Unmarshal(data, &sec)
+	if err != nil {
+		return err
+	}
+	if sec == 0 {
+		t.Time = time.Time{}
+		return nil
+	}
+	t.Time = time.Unix(sec, 0)
+	return nil
+}
+
+// Sex of the user.
+type Sex int
+
+const (
+	SexUnknown Sex = iota
+	SexFemale
+	SexMale
+)
+
+func (s Sex) String() string {
+	switch s {
+	case SexFemale:
+		return "female"
+	case SexMale:
+		return "male"
+	}
+	return "unknown"
+}
+
+// Relation is a relationship status of the user.
+type Relation int
+
+const (
+	RelationUnknown Relation = iota
+	RelationSingle
+	RelationInRelationship
+	RelationEngaged
+	RelationMarried
+	RelationComplicated
+	RelationActivelySearching
+	RelationInLove
+	RelationCivilUnion
+)
+
+func (r Relation) String() string {
+	switch r {
+	case RelationSingle:
+		return "single"
+	case RelationInRelationship:
+		return "in a relationship"
+	case RelationEngaged:
+		return "engaged"
+	case RelationMarried:
+		return "married"
+	case RelationComplicated:
+		return "it's complicated"
+	case RelationActivelySearching:
+		return "actively searching"
+	case RelationInLove:
+		return "in love"
+	case RelationCivilUnion:
+		return "in a civil union"
+	}
+	return "unknown"
+}
+
+// Platform the user was online from last time.
+// https://vk.com/dev/using_longpoll
+type Platform int
+
+const (
+	_ Platform = iota
+	PlatformMobile
+	PlatformIPhone
+	PlatformIPad
+	PlatformAndroid
+	PlatformWindowsPhone
+	PlatformWindows
+	PlatformWeb
+)
+
+func (p Platform) String() string {
+	switch p {
+	case PlatformMobile:
+		return "mobile"
+	case PlatformIPhone:
+		return "iphone"
+	case PlatformIPad:
+		return "ipad"
+	case PlatformAndroid:
+		return "android"
+	case PlatformWindowsPhone:
+		return "wphone"
+	case PlatformWindows:
+		return "windows"
+	case PlatformWeb:
+		return "web"
+	}
+	return "unknown"
+}
+
+// GroupType is a type of the community.
+type GroupType string
+
+const (
+	GroupTypeGroup GroupType = "group"
+	GroupTypePage  GroupType = "page"
+	GroupTypeEvent GroupType = "event"
+)
+
+// GroupAccess tells whether the community is closed.
+type GroupAccess int
+
+const (
+	GroupOpen GroupAccess = iota
+	GroupClosed
+	GroupPrivate
+)
+
+func (a GroupAccess) String() string {
+	switch a {
+	case GroupOpen:
+		return "open"
+	case GroupClosed:
+		return "closed"
+	case GroupPrivate:
+		return "private"
+	}
+	return "unknown"
+}
+
+// Deactivated is set if the user or
+// the community has been deleted or banned.
+type Deactivated string
+
+const (
+	NotDeactivated Deactivated = ""
+	Deleted        Deactivated = "deleted"
+	Banned         Deactivated = "banned"
+)
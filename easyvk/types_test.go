@@ -0,0 +1,78 @@
+package easyvk
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestBoolIntJSON(t *testing.T) {
+	tests := []struct {
+		data string
+		want BoolInt
+		err  bool
+	}{
+		{`1`, true, false},
+		{`0`, false, false},
+		{`2`, true, false},
+		{`true`, true, false},
+		{`false`, false, false},
+		{`null`, false, false},
+		{`"1"`, false, true},
+	}
+	for _, tt := range tests {
+		var got BoolInt
+		err := json.Unmarshal([]byte(tt.data), &got)
+		if (err != nil) != tt.err {
+			t.Errorf("%s: got error %v", tt.data, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.data, got, tt.want)
+		}
+	}
+
+	data, err := json.Marshal(struct {
+		A BoolInt `json:"a"`
+		B BoolInt `json:"b"`
+	}{true, false})
+	if err != nil || string(data) != `{"a":1,"b":0}` {
+		t.Errorf("got %s, %v", data, err)
+	}
+}
+
+func TestUnixTimeJSON(t *testing.T) {
+	tests := []struct {
+		data string
+		want time.Time
+		err  bool
+	}{
+		{`1500000000`, time.Unix(1500000000, 0), false},
+		{`"1500000000"`, time.Unix(1500000000, 0), false},
+		{`0`, time.Time{}, false},
+		{`null`, time.Time{}, false},
+		{`"yesterday"`, time.Time{}, true},
+	}
+	for _, tt := range tests {
+		var got UnixTime
+		err := json.Unmarshal([]byte(tt.data), &got)
+		if (err != nil) != tt.err {
+			t.Errorf("%s: got error %v", tt.data, err)
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.data, got, tt.want)
+		}
+	}
+
+	for _, tt := range []struct {
+		time UnixTime
+		want string
+	}{
+		{UnixTime{time.Unix(1500000000, 0)}, "1500000000"},
+		{UnixTime{}, "0"},
+	} {
+		data, err := json.Marshal(tt.time)
+		if err != nil || string(data) != tt.want {
+			t.Errorf("%v: got %s, %v, want %s", tt.time, data, err, tt.want)
+		}
+	}
+}
@@ -3,11 +3,13 @@ package easyvk
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
 	"io"
 	"io/ioutil"
 	"mime/multipart"
 	"net/http"
 	"os"
+	"path/filepath"
 )
 
 // An Upload describes a set of methods
@@ -66,3 +68,93 @@ func (u *Upload) PhotoWall(url, filePath string) (UploadPhotoWallResponse, error
 
 	return uploaded, nil
 }
+
+// albumUploadFiles is the max number of files in one album upload.
+const albumUploadFiles = 5
+
+// A UploadPhotoAlbumResponse describes an info
+// about photos uploaded into the album.
+type UploadPhotoAlbumResponse struct {
+	Server     int    `json:"server"`
+	PhotosList string `json:"photos_list"`
+	AlbumID    int    `json:"aid"`
+	Hash       string `json:"hash"`
+}
+
+// PhotoAlbum uploads up to 5 files (on filePaths) to given url.
+// Return info about uploaded photos.
+func (u *Upload) PhotoAlbum(url string, filePaths []string) (UploadPhotoAlbumResponse, error) {
+	if len(filePaths) > albumUploadFiles {
+		return UploadPhotoAlbumResponse{}, fmt.Errorf("can't upload %d files at once, max is %d", len(filePaths), albumUploadFiles)
+	}
+	files := map[string]string{}
+	for i, path := range filePaths {
+		files[fmt.Sprintf("file%d", i+1)] = path
+	}
+
+	var uploaded UploadPhotoAlbumResponse
+	err := postFiles(url, files, &uploaded)
+	if err != nil {
+		return UploadPhotoAlbumResponse{}, err
+	}
+	return uploaded, nil
+}
+
+// postFiles sends files as multipart form with
+// the given field names and decodes the response into v.
+func postFiles(url string, files map[string]string, v interface{}) error {
+	bodyBuf := &bytes.Buffer{}
+	bodyWriter := multipart.NewWriter(bodyBuf)
+
+	for field, filePath := range files {
+		fileWriter, err := bodyWriter.CreateFormFile(field, filepath.Base(filePath))
+		if err != nil {
+			return err
+		}
+		fh, err := os.Open(filePath)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(fileWriter, fh)
+		fh.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	contentType := bodyWriter.FormDataContentType()
+	bodyWriter.Close()
+
+	resp, err := http.Post(url, contentType, bodyBuf)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, v)
+}
+
+// A UploadPhotoMarketResponse describes an info
+// about uploaded photo of the product.
+type UploadPhotoMarketResponse struct {
+	Server   int    `json:"server"`
+	Photo    string `json:"photo"`
+	Hash     string `json:"hash"`
+	CropData string `json:"crop_data"`
+	CropHash string `json:"crop_hash"`
+}
+
+// PhotoMarket upload file (on filePath) to given url.
+// Return info about uploaded photo of the product.
+func (u *Upload) PhotoMarket(url, filePath string) (UploadPhotoMarketResponse, error) {
+	var uploaded UploadPhotoMarketResponse
+	err := postFiles(url, map[string]string{"file": filePath}, &uploaded)
+	if err != nil {
+		return UploadPhotoMarketResponse{}, err
+	}
+	return uploaded, nil
+}
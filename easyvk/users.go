@@ -21,26 +21,36 @@ acc — accusative
 ins — instrumental
 abl — prepositional
 */
-func (u *Users) Get(userIds []int, fields []string, nameCase string) (UsersGetResponse, error) {
+func (u *Users) Get(userIds []int, fields UserFields, nameCase string) (UsersGetResponse, error) {
+	var users UsersGetResponse
+	err := u.get(userIds, fields, nameCase, &users)
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// GetInto works like Get, but unmarshals users into v,
+// which must be a pointer to a slice of your own structs.
+// Fields are derived from json tags of the struct with UserFieldsOf.
+func (u *Users) GetInto(userIds []int, v interface{}, nameCase string) error {
+	return u.get(userIds, UserFieldsOf(v), nameCase, v)
+}
+
+func (u *Users) get(userIds []int, fields UserFields, nameCase string, v interface{}) error {
 	params := map[string]string{}
 	if len(userIds) > 0 {
 		params["user_ids"] = strings.Join(intIdsToString(userIds), ",")
 	}
 	if len(fields) > 0 {
-		params["fields"] = strings.Join(fields, ",")
+		params["fields"] = fields.String()
 	}
 	if nameCase != "" {
 		params["name_case"] = nameCase
 	}
 	resp, err := u.vk.Request("users.get", params)
 	if err != nil {
-		return nil, err
-	}
-	var users UsersGetResponse
-	err = json.Unmarshal(resp, &users)
-	if err != nil {
-		return nil, err
+		return err
 	}
-
-	return users, nil
+	return json.Unmarshal(resp, v)
 }
@@ -1,6 +1,9 @@
 package easyvk
 
-import "strconv"
+import (
+	"strconv"
+	"strings"
+)
 
 func boolConverter(itIs bool) string {
 	if itIs {
@@ -17,3 +20,25 @@ func intIdsToString(ids []int) []string {
 	}
 	return str
 }
+
+// compareVersions compares API versions like "5.63" and "5.199",
+// it returns -1, 0 or 1.
+func compareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int
+		if i < len(as) {
+			x, _ = strconv.Atoi(as[i])
+		}
+		if i < len(bs) {
+			y, _ = strconv.Atoi(bs[i])
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
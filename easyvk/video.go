@@ -1,10 +1,14 @@
 package easyvk
 
 import (
+	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 )
 
+// A Video describes a set of methods
+// to work with videos.
 // https://vk.com/dev/video
 type Video struct {
 	vk *VK
@@ -28,3 +32,428 @@ func (v *Video) DeleteComment(ownerID, commentId int) (bool, error) {
 	}
 	return ok == 1, nil
 }
+
+// VideoGetParams provides fields for Get params.
+// https://vk.com/dev/video.get
+type VideoGetParams struct {
+	OwnerID int
+	// IDs like "-4363_136089719" or "-4363_136089719_access-key".
+	Videos  []string
+	AlbumID int
+	Offset  int
+	Count   int
+	// Fill Profiles and Groups of video owners.
+	Extended bool
+}
+
+// VideoGetResponse describes a list of videos.
+// https://vk.com/dev/video.get
+type VideoGetResponse struct {
+	Count    int           `json:"count"`
+	Items    []VideoObject `json:"items"`
+	Profiles []UserObject  `json:"profiles"`
+	Groups   []GroupObject `json:"groups"`
+}
+
+// Get returns detailed information about videos.
+// https://vk.com/dev/video.get
+func (v *Video) Get(p VideoGetParams) (*VideoGetResponse, error) {
+	params := map[string]string{
+		"offset":   strconv.Itoa(p.Offset),
+		"extended": boolConverter(p.Extended),
+	}
+	if p.OwnerID != 0 {
+		params["owner_id"] = strconv.Itoa(p.OwnerID)
+	}
+	if len(p.Videos) > 0 {
+		params["videos"] = strings.Join(p.Videos, ",")
+	}
+	if p.AlbumID != 0 {
+		params["album_id"] = strconv.Itoa(p.AlbumID)
+	}
+	if p.Count != 0 {
+		params["count"] = strconv.Itoa(p.Count)
+	}
+	var videos VideoGetResponse
+	err := v.getList("video.get", params, &videos)
+	if err != nil {
+		return nil, err
+	}
+	return &videos, nil
+}
+
+// VideoSearchParams provides fields for Search params.
+// https://vk.com/dev/video.search
+type VideoSearchParams struct {
+	Q string
+	// 0 — by date, 1 — by duration, 2 — by relevance
+	Sort int
+	HD   bool
+	// Disable the safe search.
+	Adult bool
+	// comma separated list of: youtube, vimeo, short, long
+	Filters   string
+	SearchOwn bool
+	// Duration limits in seconds.
+	Longer   int
+	Shorter  int
+	Offset   int
+	Count    int
+	Extended bool
+}
+
+// VideoSearchResponse describes a list of found videos.
+// https://vk.com/dev/video.search
+type VideoSearchResponse struct {
+	Count    int           `json:"count"`
+	Items    []VideoObject `json:"items"`
+	Profiles []UserObject  `json:"profiles"`
+	Groups   []GroupObject `json:"groups"`
+}
+
+// Search returns a list of videos under the set search criterion.
+// https://vk.com/dev/video.search
+func (v *Video) Search(p VideoSearchParams) (*VideoSearchResponse, error) {
+	params := map[string]string{
+		"q":          p.Q,
+		"sort":       strconv.Itoa(p.Sort),
+		"hd":         boolConverter(p.HD),
+		"adult":      boolConverter(p.Adult),
+		"filters":    p.Filters,
+		"search_own": boolConverter(p.SearchOwn),
+		"offset":     strconv.Itoa(p.Offset),
+		"extended":   boolConverter(p.Extended),
+	}
+	if p.Longer != 0 {
+		params["longer"] = strconv.Itoa(p.Longer)
+	}
+	if p.Shorter != 0 {
+		params["shorter"] = strconv.Itoa(p.Shorter)
+	}
+	if p.Count != 0 {
+		params["count"] = strconv.Itoa(p.Count)
+	}
+	var videos VideoSearchResponse
+	err := v.getList("video.search", params, &videos)
+	if err != nil {
+		return nil, err
+	}
+	return &videos, nil
+}
+
+func (v *Video) getList(method string, params map[string]string, videos interface{}) error {
+	resp, err := v.vk.Request(method, params)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(resp, videos)
+}
+
+// VideoGetAlbumsParams provides fields for GetAlbums params.
+// https://vk.com/dev/video.getAlbums
+type VideoGetAlbumsParams struct {
+	OwnerID int
+	Offset  int
+	Count   int
+	// Fill Count and images of the albums.
+	Extended bool
+	// Include system albums "Added" and "Uploaded".
+	NeedSystem bool
+}
+
+// VideoGetAlbumsResponse describes a list of video albums.
+// https://vk.com/dev/video.getAlbums
+type VideoGetAlbumsResponse struct {
+	Count int                `json:"count"`
+	Items []VideoAlbumObject `json:"items"`
+}
+
+// GetAlbums returns a list of video albums of a user or community.
+// https://vk.com/dev/video.getAlbums
+func (v *Video) GetAlbums(p VideoGetAlbumsParams) (*VideoGetAlbumsResponse, error) {
+	params := map[string]string{
+		"offset":      strconv.Itoa(p.Offset),
+		"extended":    boolConverter(p.Extended),
+		"need_system": boolConverter(p.NeedSystem),
+	}
+	if p.OwnerID != 0 {
+		params["owner_id"] = strconv.Itoa(p.OwnerID)
+	}
+	if p.Count != 0 {
+		params["count"] = strconv.Itoa(p.Count)
+	}
+	resp, err := v.vk.Request("video.getAlbums", params)
+	if err != nil {
+		return nil, err
+	}
+	var albums VideoGetAlbumsResponse
+	err = json.Unmarshal(resp, &albums)
+	if err != nil {
+		return nil, err
+	}
+	return &albums, nil
+}
+
+// AddAlbum creates an empty video album and returns its ID.
+// groupID is set to create the album in the community,
+// privacy is used for user albums, e.g. []string{"friends"}.
+// https://vk.com/dev/video.addAlbum
+func (v *Video) AddAlbum(groupID int, title string, privacy []string) (int, error) {
+	params := map[string]string{
+		"title": title,
+	}
+	if groupID != 0 {
+		params["group_id"] = strconv.Itoa(groupID)
+	}
+	if len(privacy) > 0 {
+		params["privacy"] = strings.Join(privacy, ",")
+	}
+	resp, err := v.vk.Request("video.addAlbum", params)
+	if err != nil {
+		return 0, err
+	}
+	var album struct {
+		AlbumID int `json:"album_id"`
+	}
+	err = json.Unmarshal(resp, &album)
+	if err != nil {
+		return 0, err
+	}
+	return album.AlbumID, nil
+}
+
+// EditAlbum edits the title and privacy of the video album.
+// groupID is set if the album belongs to the community.
+// https://vk.com/dev/video.editAlbum
+func (v *Video) EditAlbum(albumID, groupID int, title string, privacy []string) (bool, error) {
+	params := map[string]string{
+		"album_id": strconv.Itoa(albumID),
+		"title":    title,
+	}
+	if groupID != 0 {
+		params["group_id"] = strconv.Itoa(groupID)
+	}
+	if len(privacy) > 0 {
+		params["privacy"] = strings.Join(privacy, ",")
+	}
+	return v.vk.requestBool("video.editAlbum", params)
+}
+
+// DeleteAlbum deletes the video album.
+// groupID is set if the album belongs to the community.
+// https://vk.com/dev/video.deleteAlbum
+func (v *Video) DeleteAlbum(albumID, groupID int) (bool, error) {
+	params := map[string]string{
+		"album_id": strconv.Itoa(albumID),
+	}
+	if groupID != 0 {
+		params["group_id"] = strconv.Itoa(groupID)
+	}
+	return v.vk.requestBool("video.deleteAlbum", params)
+}
+
+// AddToAlbum adds the video to the albums of the user or community
+// (targetID, negative for communities).
+// https://vk.com/dev/video.addToAlbum
+func (v *Video) AddToAlbum(ownerID, videoID, targetID int, albumIDs []int) (bool, error) {
+	return v.vk.requestBool("video.addToAlbum", albumVideoParams(ownerID, videoID, targetID, albumIDs))
+}
+
+// RemoveFromAlbum removes the video from the albums of the user or community
+// (targetID, negative for communities).
+// https://vk.com/dev/video.removeFromAlbum
+func (v *Video) RemoveFromAlbum(ownerID, videoID, targetID int, albumIDs []int) (bool, error) {
+	return v.vk.requestBool("video.removeFromAlbum", albumVideoParams(ownerID, videoID, targetID, albumIDs))
+}
+
+func albumVideoParams(ownerID, videoID, targetID int, albumIDs []int) map[string]string {
+	return map[string]string{
+		"owner_id":  strconv.Itoa(ownerID),
+		"video_id":  strconv.Itoa(videoID),
+		"target_id": strconv.Itoa(targetID),
+		"album_ids": strings.Join(intIdsToString(albumIDs), ","),
+	}
+}
+
+// VideoEditParams provides fields for Edit params.
+// https://vk.com/dev/video.edit
+type VideoEditParams struct {
+	OwnerID int
+	VideoID int
+	Name    string
+	Desc    string
+	// Privacy settings like "all", "friends" or "nobody", user videos only.
+	PrivacyView    []string
+	PrivacyComment []string
+	NoComments     bool
+	Repeat         bool
+}
+
+// Edit edits information about the video.
+// https://vk.com/dev/video.edit
+func (v *Video) Edit(p VideoEditParams) (bool, error) {
+	params := map[string]string{
+		"owner_id":    strconv.Itoa(p.OwnerID),
+		"video_id":    strconv.Itoa(p.VideoID),
+		"name":        p.Name,
+		"desc":        p.Desc,
+		"no_comments": boolConverter(p.NoComments),
+		"repeat":      boolConverter(p.Repeat),
+	}
+	if len(p.PrivacyView) > 0 {
+		params["privacy_view"] = strings.Join(p.PrivacyView, ",")
+	}
+	if len(p.PrivacyComment) > 0 {
+		params["privacy_comment"] = strings.Join(p.PrivacyComment, ",")
+	}
+	return v.vk.requestBool("video.edit", params)
+}
+
+// Add adds the video to the list of the user or community
+// (targetID, negative for communities) and returns its new ID.
+// https://vk.com/dev/video.add
+func (v *Video) Add(ownerID, videoID, targetID int) (int, error) {
+	params := map[string]string{
+		"video_id": strconv.Itoa(videoID),
+		"owner_id": strconv.Itoa(ownerID),
+	}
+	if targetID != 0 {
+		params["target_id"] = strconv.Itoa(targetID)
+	}
+	resp, err := v.vk.Request("video.add", params)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(string(resp))
+}
+
+// Delete deletes the video from the list of the user or community
+// (targetID, negative for communities).
+// https://vk.com/dev/video.delete
+func (v *Video) Delete(ownerID, videoID, targetID int) (bool, error) {
+	params := map[string]string{
+		"video_id": strconv.Itoa(videoID),
+		"owner_id": strconv.Itoa(ownerID),
+	}
+	if targetID != 0 {
+		params["target_id"] = strconv.Itoa(targetID)
+	}
+	return v.vk.requestBool("video.delete", params)
+}
+
+// Restore restores a previously deleted video.
+// https://vk.com/dev/video.restore
+func (v *Video) Restore(ownerID, videoID int) (bool, error) {
+	params := map[string]string{
+		"video_id": strconv.Itoa(videoID),
+		"owner_id": strconv.Itoa(ownerID),
+	}
+	return v.vk.requestBool("video.restore", params)
+}
+
+// VideoGetCommentsParams provides fields for GetComments params.
+// https://vk.com/dev/video.getComments
+type VideoGetCommentsParams struct {
+	OwnerID   int
+	VideoID   int
+	NeedLikes bool
+	// Comment to start from, Offset is counted from it.
+	StartCommentID int
+	Offset         int
+	Count          int
+	// one of: asc (default), desc
+	Sort string
+	// Fill Profiles and Groups of comment authors.
+	Extended bool
+	Fields   UserFields
+}
+
+// VideoGetCommentsResponse describes a list of comments on the video.
+// https://vk.com/dev/video.getComments
+type VideoGetCommentsResponse struct {
+	Count    int             `json:"count"`
+	Items    []CommentObject `json:"items"`
+	Profiles []UserObject    `json:"profiles"`
+	Groups   []GroupObject   `json:"groups"`
+}
+
+// GetComments returns a list of comments on the video.
+// https://vk.com/dev/video.getComments
+func (v *Video) GetComments(p VideoGetCommentsParams) (*VideoGetCommentsResponse, error) {
+	params := map[string]string{
+		"owner_id":   strconv.Itoa(p.OwnerID),
+		"video_id":   strconv.Itoa(p.VideoID),
+		"need_likes": boolConverter(p.NeedLikes),
+		"offset":     strconv.Itoa(p.Offset),
+		"sort":       p.Sort,
+		"extended":   boolConverter(p.Extended),
+		"fields":     p.Fields.String(),
+	}
+	if p.StartCommentID != 0 {
+		params["start_comment_id"] = strconv.Itoa(p.StartCommentID)
+	}
+	if p.Count != 0 {
+		params["count"] = strconv.Itoa(p.Count)
+	}
+	resp, err := v.vk.Request("video.getComments", params)
+	if err != nil {
+		return nil, err
+	}
+	var comments VideoGetCommentsResponse
+	err = json.Unmarshal(resp, &comments)
+	if err != nil {
+		return nil, err
+	}
+	return &comments, nil
+}
+
+// VideoCreateCommentParams provides fields for CreateComment params.
+// https://vk.com/dev/video.createComment
+type VideoCreateCommentParams struct {
+	OwnerID int
+	VideoID int
+	Message string
+	// comma separated list like photo100172_166443618
+	Attachments    string
+	FromGroup      bool
+	ReplyToComment int
+	StickerID      int
+	GUID           string
+}
+
+// CreateComment adds a new comment on the video
+// and returns ID of the comment.
+// https://vk.com/dev/video.createComment
+func (v *Video) CreateComment(p VideoCreateCommentParams) (int, error) {
+	params := map[string]string{
+		"owner_id":    strconv.Itoa(p.OwnerID),
+		"video_id":    strconv.Itoa(p.VideoID),
+		"message":     p.Message,
+		"attachments": p.Attachments,
+		"from_group":  boolConverter(p.FromGroup),
+		"guid":        p.GUID,
+	}
+	if p.ReplyToComment != 0 {
+		params["reply_to_comment"] = strconv.Itoa(p.ReplyToComment)
+	}
+	if p.StickerID != 0 {
+		params["sticker_id"] = strconv.Itoa(p.StickerID)
+	}
+	resp, err := v.vk.Request("video.createComment", params)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(string(resp))
+}
+
+// EditComment edits a comment on the video.
+// https://vk.com/dev/video.editComment
+func (v *Video) EditComment(ownerID, commentID int, message, attachments string) (bool, error) {
+	params := map[string]string{
+		"owner_id":    strconv.Itoa(ownerID),
+		"comment_id":  strconv.Itoa(commentID),
+		"message":     message,
+		"attachments": attachments,
+	}
+	return v.vk.requestBool("video.editComment", params)
+}
@@ -0,0 +1,205 @@
+package easyvk
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// videoServer answers every method with its response from responses
+// and keeps the method and the form of the last request.
+func videoServer(responses map[string]string, method *string, form *url.Values) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		*method = strings.TrimPrefix(r.URL.Path, "/")
+		*form = r.Form
+		fmt.Fprintf(w, `{"response": %s}`, responses[*method])
+	}))
+}
+
+// checkForm reports params which differ in form.
+func checkForm(t *testing.T, name string, form url.Values, params map[string]string) {
+	t.Helper()
+	for key, value := range params {
+		if got := form.Get(key); got != value {
+			t.Errorf("%s: got %s=%q, want %q", name, key, got, value)
+		}
+	}
+}
+
+func TestVideoMethods(t *testing.T) {
+	var method string
+	var form url.Values
+	srv := videoServer(map[string]string{
+		"video.addAlbum":        `{"album_id": 7}`,
+		"video.editAlbum":       `1`,
+		"video.deleteAlbum":     `1`,
+		"video.addToAlbum":      `1`,
+		"video.removeFromAlbum": `1`,
+		"video.edit":            `1`,
+		"video.add":             `456239018`,
+		"video.delete":          `1`,
+		"video.restore":         `1`,
+		"video.createComment":   `15`,
+		"video.editComment":     `1`,
+	}, &method, &form)
+	defer srv.Close()
+	vk := WithToken("token")
+	vk.ApiUrl = srv.URL + "/"
+
+	tests := []struct {
+		method string
+		call   func() (interface{}, error)
+		params map[string]string
+		want   interface{}
+	}{
+		{
+			"video.addAlbum",
+			func() (interface{}, error) { return vk.Video.AddAlbum(5, "Trips", nil) },
+			map[string]string{"group_id": "5", "title": "Trips", "privacy": ""},
+			7,
+		},
+		{
+			"video.addAlbum",
+			func() (interface{}, error) { return vk.Video.AddAlbum(0, "Trips", []string{"friends", "-3"}) },
+			map[string]string{"group_id": "", "privacy": "friends,-3"},
+			7,
+		},
+		{
+			"video.editAlbum",
+			func() (interface{}, error) { return vk.Video.EditAlbum(7, 5, "Travels", nil) },
+			map[string]string{"album_id": "7", "group_id": "5", "title": "Travels"},
+			true,
+		},
+		{
+			"video.deleteAlbum",
+			func() (interface{}, error) { return vk.Video.DeleteAlbum(7, 0) },
+			map[string]string{"album_id": "7", "group_id": ""},
+			true,
+		},
+		{
+			"video.addToAlbum",
+			func() (interface{}, error) { return vk.Video.AddToAlbum(-5, 18, -5, []int{7, 8}) },
+			map[string]string{"owner_id": "-5", "video_id": "18", "target_id": "-5", "album_ids": "7,8"},
+			true,
+		},
+		{
+			"video.removeFromAlbum",
+			func() (interface{}, error) { return vk.Video.RemoveFromAlbum(1, 18, 2, []int{7}) },
+			map[string]string{"owner_id": "1", "video_id": "18", "target_id": "2", "album_ids": "7"},
+			true,
+		},
+		{
+			"video.edit",
+			func() (interface{}, error) {
+				return vk.Video.Edit(VideoEditParams{OwnerID: 1, VideoID: 18, Name: "Sea", PrivacyView: []string{"friends"}, Repeat: true})
+			},
+			map[string]string{"owner_id": "1", "video_id": "18", "name": "Sea", "privacy_view": "friends", "privacy_comment": "", "repeat": "1", "no_comments": "0"},
+			true,
+		},
+		{
+			"video.add",
+			func() (interface{}, error) { return vk.Video.Add(1, 18, -5) },
+			map[string]string{"owner_id": "1", "video_id": "18", "target_id": "-5"},
+			456239018,
+		},
+		{
+			"video.delete",
+			func() (interface{}, error) { return vk.Video.Delete(1, 18, 0) },
+			map[string]string{"owner_id": "1", "video_id": "18", "target_id": ""},
+			true,
+		},
+		{
+			"video.restore",
+			func() (interface{}, error) { return vk.Video.Restore(1, 18) },
+			map[string]string{"owner_id": "1", "video_id": "18"},
+			true,
+		},
+		{
+			"video.createComment",
+			func() (interface{}, error) {
+				return vk.Video.CreateComment(VideoCreateCommentParams{OwnerID: 1, VideoID: 18, Message: "hi", ReplyToComment: 3})
+			},
+			map[string]string{"owner_id": "1", "video_id": "18", "message": "hi", "reply_to_comment": "3", "sticker_id": "", "from_group": "0"},
+			15,
+		},
+		{
+			"video.editComment",
+			func() (interface{}, error) { return vk.Video.EditComment(1, 15, "hello", "photo1_2") },
+			map[string]string{"owner_id": "1", "comment_id": "15", "message": "hello", "attachments": "photo1_2"},
+			true,
+		},
+	}
+	for _, tt := range tests {
+		got, err := tt.call()
+		if err != nil {
+			t.Errorf("%s: %v", tt.method, err)
+			continue
+		}
+		if method != tt.method {
+			t.Errorf("%s: called %s", tt.method, method)
+		}
+		checkForm(t, tt.method, form, tt.params)
+		if got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.method, got, tt.want)
+		}
+	}
+}
+
+func TestVideoLists(t *testing.T) {
+	var method string
+	var form url.Values
+	srv := videoServer(map[string]string{
+		"video.get": `{"count": 2, "items": [{"id": 18, "owner_id": -5, "title": "Sea", "duration": 60, "date": 1700000000}],
+			"profiles": [], "groups": [{"id": 5, "name": "Example"}]}`,
+		"video.search":    `{"count": 1, "items": [{"id": 19, "owner_id": 1, "title": "Cats"}]}`,
+		"video.getAlbums": `{"count": 1, "items": [{"id": -2, "owner_id": 1, "title": "Added", "count": 4, "is_system": 1}]}`,
+		"video.getComments": `{"count": 1, "items": [{"id": 15, "from_id": 1, "date": 1700000000, "text": "hi"}],
+			"profiles": [{"id": 1, "first_name": "Ivan"}], "groups": []}`,
+	}, &method, &form)
+	defer srv.Close()
+	vk := WithToken("token")
+	vk.ApiUrl = srv.URL + "/"
+
+	videos, err := vk.Video.Get(VideoGetParams{Videos: []string{"-5_18", "-5_19_key"}, Extended: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, method, form, map[string]string{"videos": "-5_18,-5_19_key", "extended": "1", "owner_id": "", "album_id": "", "count": ""})
+	if videos.Count != 2 || len(videos.Items) != 1 || videos.Items[0].Title != "Sea" || videos.Items[0].Date.Unix() != 1700000000 {
+		t.Errorf("got videos %+v", videos)
+	}
+	if len(videos.Groups) != 1 || videos.Groups[0].Name != "Example" {
+		t.Errorf("got groups %+v", videos.Groups)
+	}
+
+	found, err := vk.Video.Search(VideoSearchParams{Q: "cats", Sort: 2, HD: true, Longer: 60, Count: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, method, form, map[string]string{"q": "cats", "sort": "2", "hd": "1", "longer": "60", "shorter": "", "count": "10"})
+	if found.Count != 1 || found.Items[0].ID != 19 {
+		t.Errorf("got videos %+v", found)
+	}
+
+	albums, err := vk.Video.GetAlbums(VideoGetAlbumsParams{OwnerID: 1, NeedSystem: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, method, form, map[string]string{"owner_id": "1", "need_system": "1", "extended": "0"})
+	if albums.Count != 1 || albums.Items[0].Title != "Added" || !albums.Items[0].IsSystem {
+		t.Errorf("got albums %+v", albums)
+	}
+
+	comments, err := vk.Video.GetComments(VideoGetCommentsParams{OwnerID: 1, VideoID: 18, Sort: "desc", Extended: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkForm(t, method, form, map[string]string{"owner_id": "1", "video_id": "18", "sort": "desc", "extended": "1", "start_comment_id": ""})
+	if comments.Count != 1 || comments.Items[0].Text != "hi" || len(comments.Profiles) != 1 {
+		t.Errorf("got comments %+v", comments)
+	}
+}
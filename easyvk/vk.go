@@ -4,23 +4,24 @@ package easyvk
 
 import (
 	"encoding/json"
-	"errors"
 	"fmt"
-	"golang.org/x/net/html"
-	"io"
 	"io/ioutil"
 	"net/http"
-	"net/http/cookiejar"
 	"net/url"
+	"strconv"
+	"strings"
 	"time"
 )
 
 const (
 	version = "5.63"
-	apiURL  = "https://api.vk.com/method/"
-	authURL = "https://oauth.vk.com/authorize?" +
+	// reactionsVersion is the API version which sends
+	// reactions on posts and comments.
+	reactionsVersion = "5.199"
+	apiURL           = "https://api.vk.com/method/"
+	authURL          = "https://oauth.vk.com/authorize?" +
 		"client_id=%s" +
-		"&scope=%s" +
+		"&scope=%d" +
 		"&redirect_uri=https://oauth.vk.com/blank.html" +
 		"&display=wap" +
 		"&v=%s" +
@@ -46,6 +47,22 @@ type VK struct {
 	Users       Users
 	Video       Video
 	Market      Market
+	Secure      Secure
+	// Kind of AccessToken, UnknownToken if it's not known.
+	// Requests the token can't make fail before sending,
+	// see CanCall and DetectToken.
+	TokenKind TokenKind
+	// Permissions of the user token, 0 if they are not known.
+	Scope Scope
+	// Permissions of the community token, 0 if they are not known.
+	GroupScope GroupScope
+	// Secret of the application, it's sent with secure methods.
+	ClientSecret string
+	// If set, requests are sent with tokens of the pool
+	// and AccessToken is not used.
+	Pool *TokenPool
+
+	pinned string
 }
 
 func (vk *VK) SetDebug(val bool) {
@@ -59,6 +76,11 @@ func WithToken(token string) *VK {
 	vk.ApiUrl = apiURL
 	vk.AccessToken = token
 	vk.Version = version
+	vk.initSections()
+	return vk
+}
+
+func (vk *VK) initSections() {
 	vk.Account = Account{vk}
 	vk.Board = Board{vk}
 	vk.Fave = Fave{vk}
@@ -71,109 +93,75 @@ func WithToken(token string) *VK {
 	vk.Users = Users{vk}
 	vk.Video = Video{vk}
 	vk.Market = Market{vk}
-	return vk
+	vk.Secure = Secure{vk}
 }
 
-// WithAuth helps to initialize your VK object
-// with signing in by login, password, client id and scope
-// Scope must be a string like "friends,wall"
-func WithAuth(login, password, clientID, scope string) (*VK, error) {
-	u := fmt.Sprintf(authURL, clientID, scope, version)
-	jar, _ := cookiejar.New(nil)
-	client := &http.Client{
-		Jar: jar,
-	}
+// poolAttempts limits how many tokens of the pool
+// a request tries before it returns the error.
+const poolAttempts = 3
 
-	resp, err := client.Get(u)
-	if err != nil {
-		return nil, err
-	}
-	defer resp.Body.Close()
-
-	args, u := parseForm(resp.Body)
+// Request provides access to VK API methods.
+func (vk *VK) Request(method string, params map[string]string) ([]byte, error) {
+	return vk.request(method, params, vk.Version)
+}
 
-	args.Add("email", login)
-	args.Add("pass", password)
+// requestVersion sends the request with at least the given API version,
+// for methods whose fields are sent only by newer versions.
+func (vk *VK) requestVersion(method string, params map[string]string, minVersion string) ([]byte, error) {
+	v := vk.Version
+	if compareVersions(v, minVersion) < 0 {
+		v = minVersion
+	}
+	return vk.request(method, params, v)
+}
 
-	resp, err = client.PostForm(u, args)
+func (vk *VK) request(method string, params map[string]string, version string) ([]byte, error) {
+	err := vk.CanCall(method)
 	if err != nil {
 		return nil, err
 	}
+	if vk.Pool == nil {
+		return vk.send(method, params, vk.AccessToken, version)
+	}
 
-	if resp.Request.URL.Path != "/blank.html" {
-		args, u := parseForm(resp.Body)
-		resp, err := client.PostForm(u, args)
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		token, err := vk.Pool.acquire(vk.pinned)
 		if err != nil {
+			if lastErr != nil {
+				return nil, lastErr
+			}
 			return nil, err
 		}
-		defer resp.Body.Close()
-
-		if resp.Request.URL.Path != "/blank.html" {
-			return nil, errors.New("can't log in")
+		resp, err := vk.send(method, params, token, version)
+		if err == nil {
+			return resp, nil
 		}
+		vk.Pool.report(token, err)
+		// another token can make the request if this one is limited
+		if vk.pinned != "" || attempt == poolAttempts || !isTokenError(err) {
+			return nil, err
+		}
+		lastErr = err
 	}
-
-	urlArgs, err := url.ParseQuery(resp.Request.URL.Fragment)
-	if err != nil {
-		return nil, err
-	}
-
-	return WithToken(urlArgs["access_token"][0]), nil
 }
 
-func parseForm(body io.ReadCloser) (url.Values, string) {
-	tokenizer := html.NewTokenizer(body)
-
-	u := ""
-	formData := map[string]string{}
-
-	end := false
-	for !end {
-		tag := tokenizer.Next()
-
-		switch tag {
-		case html.ErrorToken:
-			end = true
-		case html.StartTagToken:
-			switch token := tokenizer.Token(); token.Data {
-			case "form":
-				for _, attr := range token.Attr {
-					if attr.Key == "action" {
-						u = attr.Val
-					}
-				}
-			case "input":
-				if token.Attr[1].Val == "_origin" {
-					formData["_origin"] = token.Attr[2].Val
-				}
-				if token.Attr[1].Val == "to" {
-					formData["to"] = token.Attr[2].Val
-				}
-			}
-		case html.SelfClosingTagToken:
-			switch token := tokenizer.Token(); token.Data {
-			case "input":
-				if token.Attr[1].Val == "ip_h" {
-					formData["ip_h"] = token.Attr[2].Val
-				}
-				if token.Attr[1].Val == "lg_h" {
-					formData["lg_h"] = token.Attr[2].Val
-				}
-			}
-		}
+// isTokenError reports whether err is caused by
+// the token rather than by the request itself.
+func isTokenError(err error) bool {
+	vkErr, ok := err.(*Error)
+	if !ok {
+		return false
 	}
-
-	args := url.Values{}
-
-	for key, val := range formData {
-		args.Add(key, val)
+	switch vkErr.Code {
+	case authFailedCode, tooManyRequestsCode, rateLimitCode:
+		return true
 	}
-
-	return args, u
+	return false
 }
 
-// Request provides access to VK API methods.
-func (vk *VK) Request(method string, params map[string]string) ([]byte, error) {
+// send makes the request with the token.
+func (vk *VK) send(method string, params map[string]string, token, version string) ([]byte, error) {
 	u, err := url.Parse(vk.ApiUrl + method)
 	if err != nil {
 		return nil, err
@@ -183,8 +171,8 @@ func (vk *VK) Request(method string, params map[string]string) ([]byte, error) {
 	for k, v := range params {
 		query.Set(k, v)
 	}
-	query.Set("access_token", vk.AccessToken)
-	query.Set("v", vk.Version)
+	query.Set("access_token", token)
+	query.Set("v", version)
 	u.RawQuery = query.Encode()
 	start := time.Now()
 	resp, err := http.Get(u.String())
@@ -198,7 +186,7 @@ func (vk *VK) Request(method string, params map[string]string) ([]byte, error) {
 		if vk.Debug {
 			fmt.Printf(
 				"[VkApi] Call method %s, with params: %#v \n[VkApi] Network error %#v \n[VkApi] take: %s\n",
-				method, params, err,
+				method, redactParams(params), err,
 				time.Since(start),
 			)
 		}
@@ -206,8 +194,9 @@ func (vk *VK) Request(method string, params map[string]string) ([]byte, error) {
 	}
 
 	var handler struct {
-		Error    *Error
-		Response json.RawMessage
+		Error         *Error
+		Response      json.RawMessage
+		ExecuteErrors []ExecuteCallError `json:"execute_errors"`
 	}
 	err = json.Unmarshal(body, &handler)
 
@@ -215,7 +204,7 @@ func (vk *VK) Request(method string, params map[string]string) ([]byte, error) {
 		if vk.Debug {
 			fmt.Printf(
 				"[VkApi] Call method %s, with params: %#v \n[VkApi] Vk error %s \n[VkApi] take: %s\n",
-				method, params, body,
+				method, redactParams(params), handler.Error,
 				time.Since(start),
 			)
 		}
@@ -225,10 +214,53 @@ func (vk *VK) Request(method string, params map[string]string) ([]byte, error) {
 	if vk.Debug {
 		fmt.Printf(
 			"[VkApi] Call method %s, with params: %#v \n[VkApi] Response %v \n[VkApi] take: %s\n",
-			method, params, string(handler.Response),
+			method, redactParams(params), string(handler.Response),
 			time.Since(start),
 		)
 	}
 
+	if len(handler.ExecuteErrors) > 0 {
+		return nil, &ExecuteError{handler.ExecuteErrors, handler.Response}
+	}
 	return handler.Response, nil
 }
+
+// redactParams returns a copy of params without
+// secrets and tokens to print them in debug mode.
+func redactParams(params map[string]string) map[string]string {
+	redacted := make(map[string]string, len(params))
+	for k, v := range params {
+		if strings.Contains(k, "secret") || strings.Contains(k, "token") || strings.Contains(k, "password") {
+			v = "[redacted]"
+		}
+		redacted[k] = v
+	}
+	return redacted
+}
+
+// Execute runs a VKScript code that can call
+// up to 25 API methods within one request.
+// If some of the calls fail it returns *ExecuteError
+// which still holds the response of the code.
+// https://vk.com/dev/execute
+func (vk *VK) Execute(code string) ([]byte, error) {
+	params := map[string]string{
+		"code": code,
+	}
+	return vk.Request("execute", params)
+}
+
+// requestBool sends the request of the method
+// which returns 1 on success.
+func (vk *VK) requestBool(method string, params map[string]string) (bool, error) {
+	resp, err := vk.Request(method, params)
+	if err != nil {
+		return false, err
+	}
+
+	ok, err := strconv.ParseUint(string(resp), 10, 8)
+	if err != nil {
+		return false, err
+	}
+	return ok == 1, nil
+}
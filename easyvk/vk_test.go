@@ -0,0 +1,28 @@
+package easyvk
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedactParams(t *testing.T) {
+	params := map[string]string{
+		"client_secret": "secret",
+		"access_token":  "token",
+		"token":         "user token",
+		"user_id":       "1",
+	}
+	got := redactParams(params)
+	want := map[string]string{
+		"client_secret": "[redacted]",
+		"access_token":  "[redacted]",
+		"token":         "[redacted]",
+		"user_id":       "1",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if params["client_secret"] != "secret" {
+		t.Error("params are changed")
+	}
+}
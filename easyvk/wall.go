@@ -1,9 +1,11 @@
 package easyvk
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 )
 
 // A Wall describes a set of methods
@@ -131,3 +133,171 @@ func (w *Wall) CreateComment(p CreateCommentParams) (int, error) {
 	}
 	return info.CommentID, nil
 }
+
+// WallGetCommentsParams provides fields for GetComments params.
+// https://vk.com/dev/wall.getComments
+type WallGetCommentsParams struct {
+	OwnerID   int
+	PostID    int
+	NeedLikes bool
+	// Comment to start from, Offset is counted from it.
+	StartCommentID int
+	Offset         int
+	Count          int
+	// one of: asc (default), desc
+	Sort string
+	// Fill Profiles and Groups of comment authors.
+	Extended bool
+	Fields   UserFields
+	// Set to get replies to the comment.
+	CommentID int
+	// Number of replies returned in Thread of every comment.
+	ThreadItemsCount int
+}
+
+// WallGetCommentsResponse describes a list of comments on the post.
+// https://vk.com/dev/wall.getComments
+type WallGetCommentsResponse struct {
+	Count    int             `json:"count"`
+	Items    []CommentObject `json:"items"`
+	Profiles []UserObject    `json:"profiles"`
+	Groups   []GroupObject   `json:"groups"`
+	// sets of reactions used by comments
+	ReactionSets []ReactionSet `json:"reaction_sets"`
+}
+
+// GetComments returns a list of comments on a post.
+// Like Get, it's sent with API version 5.199 or newer.
+// https://vk.com/dev/wall.getComments
+func (w *Wall) GetComments(p WallGetCommentsParams) (*WallGetCommentsResponse, error) {
+	params := map[string]string{
+		"owner_id":   fmt.Sprint(p.OwnerID),
+		"post_id":    fmt.Sprint(p.PostID),
+		"need_likes": boolConverter(p.NeedLikes),
+		"offset":     fmt.Sprint(p.Offset),
+		"sort":       p.Sort,
+		"extended":   boolConverter(p.Extended),
+		"fields":     p.Fields.String(),
+	}
+	if p.StartCommentID != 0 {
+		params["start_comment_id"] = fmt.Sprint(p.StartCommentID)
+	}
+	if p.Count != 0 {
+		params["count"] = fmt.Sprint(p.Count)
+	}
+	if p.CommentID != 0 {
+		params["comment_id"] = fmt.Sprint(p.CommentID)
+	}
+	if p.ThreadItemsCount != 0 {
+		params["thread_items_count"] = fmt.Sprint(p.ThreadItemsCount)
+	}
+	resp, err := w.vk.requestVersion("wall.getComments", params, reactionsVersion)
+	if err != nil {
+		return nil, err
+	}
+	var comments WallGetCommentsResponse
+	err = json.Unmarshal(resp, &comments)
+	if err != nil {
+		return nil, err
+	}
+	return &comments, nil
+}
+
+// WallGetParams provides fields for Get params.
+// https://vk.com/dev/wall.get
+type WallGetParams struct {
+	// Owner of the wall, negative for communities.
+	// Domain is used if it's not set.
+	OwnerID int
+	Domain  string
+	Offset  int
+	Count   int
+	// one of: all (default), owner, others, postponed, suggests, donut
+	Filter string
+	// Fill Profiles and Groups of post authors.
+	Extended bool
+	Fields   UserFields
+}
+
+// WallGetResponse describes a list of posts on the wall.
+// https://vk.com/dev/wall.get
+type WallGetResponse struct {
+	Count    int           `json:"count"`
+	Items    []PostObject  `json:"items"`
+	Profiles []UserObject  `json:"profiles"`
+	Groups   []GroupObject `json:"groups"`
+	// sets of reactions used by posts
+	ReactionSets []ReactionSet `json:"reaction_sets"`
+}
+
+// Get returns a list of posts on the wall.
+// It's sent with API version 5.199 which returns reactions,
+// unless VK.Version is newer, so fields of the response may differ
+// from methods sent with the default version.
+// https://vk.com/dev/wall.get
+func (w *Wall) Get(p WallGetParams) (*WallGetResponse, error) {
+	params := map[string]string{
+		"offset":   strconv.Itoa(p.Offset),
+		"extended": boolConverter(p.Extended),
+		"fields":   p.Fields.String(),
+	}
+	if p.OwnerID != 0 {
+		params["owner_id"] = strconv.Itoa(p.OwnerID)
+	}
+	if p.Domain != "" {
+		params["domain"] = p.Domain
+	}
+	if p.Count != 0 {
+		params["count"] = strconv.Itoa(p.Count)
+	}
+	if p.Filter != "" {
+		params["filter"] = p.Filter
+	}
+	resp, err := w.vk.requestVersion("wall.get", params, reactionsVersion)
+	if err != nil {
+		return nil, err
+	}
+	var posts WallGetResponse
+	err = json.Unmarshal(resp, &posts)
+	if err != nil {
+		return nil, err
+	}
+	return &posts, nil
+}
+
+// WallGetByIdResponse describes posts by their IDs.
+// https://vk.com/dev/wall.getById
+type WallGetByIdResponse struct {
+	Items    []PostObject  `json:"items"`
+	Profiles []UserObject  `json:"profiles"`
+	Groups   []GroupObject `json:"groups"`
+	// sets of reactions used by posts
+	ReactionSets []ReactionSet `json:"reaction_sets"`
+}
+
+// GetById returns posts by their IDs like "-1_123",
+// the owner ID and the post ID separated by underscore.
+// Like Get, it's sent with API version 5.199 or newer.
+// https://vk.com/dev/wall.getById
+func (w *Wall) GetById(posts []string, extended bool, fields UserFields) (*WallGetByIdResponse, error) {
+	params := map[string]string{
+		"posts":    strings.Join(posts, ","),
+		"extended": boolConverter(extended),
+		"fields":   fields.String(),
+	}
+	resp, err := w.vk.requestVersion("wall.getById", params, reactionsVersion)
+	if err != nil {
+		return nil, err
+	}
+	var res WallGetByIdResponse
+	// older versions send the array of posts without extended
+	if bytes.HasPrefix(bytes.TrimSpace(resp), []byte("[")) {
+		err = json.Unmarshal(resp, &res.Items)
+	} else {
+		err = json.Unmarshal(resp, &res)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
@@ -0,0 +1,96 @@
+package easyvk
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// wallServer answers every request with the response
+// and keeps the API version of the last request.
+func wallServer(response string, v *string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*v = r.FormValue("v")
+		fmt.Fprintf(w, `{"response": %s}`, response)
+	}))
+}
+
+const wallPost = `{"id": 5, "owner_id": -1, "from_id": -1, "text": "hi",
+	"reaction_set_id": "reactions",
+	"reactions": {"count": 3, "user_reaction": 1, "items": [{"id": 0, "count": 2}, {"id": 1, "count": 1}]}}`
+
+func TestWallReactions(t *testing.T) {
+	var v string
+	srv := wallServer(`{"count": 1, "items": [`+wallPost+`],
+		"reaction_sets": [{"id": "reactions", "items": [{"id": 0, "title": "Like"}, {"id": 1, "title": "Love"}]}]}`, &v)
+	defer srv.Close()
+	vk := WithToken("token")
+	vk.ApiUrl = srv.URL + "/"
+
+	posts, err := vk.Wall.Get(WallGetParams{OwnerID: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != reactionsVersion {
+		t.Errorf("sent with version %s", v)
+	}
+	post := posts.Items[0]
+	if post.ReactionSetID != "reactions" || post.Reactions == nil {
+		t.Fatalf("got post %+v", post)
+	}
+	if post.Reactions.Count != 3 || post.Reactions.ByID(0) != 2 || *post.Reactions.UserReaction != 1 {
+		t.Errorf("got reactions %+v", post.Reactions)
+	}
+	if len(posts.ReactionSets) != 1 || posts.ReactionSets[0].Items[1].Title != "Love" {
+		t.Errorf("got reaction sets %+v", posts.ReactionSets)
+	}
+
+	// newer version set by the caller is kept
+	vk.Version = "5.200"
+	_, err = vk.Wall.GetComments(WallGetCommentsParams{OwnerID: -1, PostID: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v != "5.200" {
+		t.Errorf("sent with version %s", v)
+	}
+}
+
+func TestWallGetById(t *testing.T) {
+	for _, response := range []string{
+		`[` + wallPost + `]`,
+		`{"items": [` + wallPost + `], "profiles": [], "groups": []}`,
+	} {
+		var v string
+		srv := wallServer(response, &v)
+		vk := WithToken("token")
+		vk.ApiUrl = srv.URL + "/"
+		posts, err := vk.Wall.GetById([]string{"-1_5"}, false, nil)
+		srv.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(posts.Items) != 1 || posts.Items[0].ID != 5 || posts.Items[0].Reactions.ByID(1) != 1 {
+			t.Errorf("got %+v", posts)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"5.63", "5.199", -1},
+		{"5.199", "5.63", 1},
+		{"5.199", "5.199", 0},
+		{"5", "5.0", 0},
+		{"6.1", "5.199", 1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("%s vs %s: got %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}